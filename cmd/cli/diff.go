@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/utils"
+	"github.com/zguydev/openapi-filter/pkg/diff"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff base_spec revision_spec [--format text|json] [--fail-on-breaking]",
+	Short: "Compare two OpenAPI specs, e.g. the source spec and the filtered spec",
+	Args:  cobra.ExactArgs(2),
+	Run:   runDiff,
+}
+
+func init() {
+	addConfigFlags(diffCmd.Flags())
+	diffCmd.Flags().String("format", formatText, "Output format: text or json")
+	diffCmd.Flags().Bool("fail-on-breaking", false, "Exit with non-zero code if there are breaking changes")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) {
+	fallbackLogger := utils.NewFallbackLogger()
+	defer fallbackLogger.Sync() //nolint:errcheck
+
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		fallbackLogger.Fatal("failed to get format flag", zap.Error(err))
+	}
+	if format != formatText && format != formatJSON {
+		fallbackLogger.Fatal("unsupported output format", zap.String("format", format))
+	}
+	failOnBreaking, err := cmd.Flags().GetBool("fail-on-breaking")
+	if err != nil {
+		fallbackLogger.Fatal("failed to get fail-on-breaking flag", zap.Error(err))
+	}
+
+	cfg, logger := setup(cmd, fallbackLogger, "stderr")
+	baseSpec := loadInputSpec(cfg, logger, args[0])
+	revisionSpec := loadInputSpec(cfg, logger, args[1])
+
+	result := diff.Compare(baseSpec, revisionSpec)
+	out := cmd.OutOrStdout()
+	if format == formatJSON {
+		err = writeJSON(out, result)
+	} else {
+		err = writeDiffText(out, result)
+	}
+	if err != nil {
+		logger.Error("failed to write diff", zap.Error(err))
+		os.Exit(1)
+	}
+	if failOnBreaking && result.HasBreaking() {
+		os.Exit(1)
+	}
+}
+
+func writeDiffText(w io.Writer, result *diff.Result) error {
+	var breaking, other []diff.Change
+	for _, change := range result.Changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		} else {
+			other = append(other, change)
+		}
+	}
+
+	var s strings.Builder
+	if len(result.Changes) == 0 {
+		s.WriteString("No changes\n")
+	}
+	for _, group := range []struct {
+		title   string
+		changes []diff.Change
+	}{
+		{"Breaking changes", breaking},
+		{"Non-breaking changes", other},
+	} {
+		if len(group.changes) == 0 {
+			continue
+		}
+		if s.Len() > 0 {
+			s.WriteByte('\n')
+		}
+		fmt.Fprintf(&s, "%s (%d):\n", group.title, len(group.changes))
+		for _, change := range group.changes {
+			fmt.Fprintf(&s, "  %s %s\n", changeSign(change.Kind), change)
+		}
+	}
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+func changeSign(kind diff.ChangeKind) string {
+	switch kind {
+	case diff.ChangeAdded:
+		return "+"
+	case diff.ChangeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
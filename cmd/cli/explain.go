@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/refs"
+	"github.com/zguydev/openapi-filter/internal/utils"
+	"github.com/zguydev/openapi-filter/pkg/filter"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain input_spec component_ref [--config filter_config]",
+	Short: "Print the shortest chain from a selected operation to a component",
+	Long: "Print the shortest chain of refs from a selected operation or component " +
+		"to the component, explaining why it is included in the filtered spec. " +
+		"The component ref may be given as #/components/schemas/Pet, " +
+		"components/schemas/Pet or schemas/Pet.",
+	Args: cobra.ExactArgs(2),
+	Run:  explain,
+}
+
+func init() {
+	addConfigFlags(explainCmd.Flags())
+	rootCmd.AddCommand(explainCmd)
+}
+
+func explain(cmd *cobra.Command, args []string) {
+	fallbackLogger := utils.NewFallbackLogger()
+	defer fallbackLogger.Sync() //nolint:errcheck
+
+	cfg, logger := setup(cmd, fallbackLogger, "stderr")
+	inputSpec := loadInputSpec(cfg, logger, args[0])
+	ref := refs.NormalizeRef(args[1])
+
+	oaf := filter.NewOpenAPISpecFilter(cfg, logger)
+	if _, err := oaf.Filter(inputSpec); err != nil {
+		logger.Error("filter on spec failed", zap.Error(err))
+		os.Exit(1)
+	}
+
+	var s strings.Builder
+	for _, comp := range oaf.Report().Components {
+		if comp.Ref == ref && comp.Selected {
+			fmt.Fprintf(&s, "%s is selected in the filter config\n", ref)
+		}
+	}
+	if chain := oaf.Explain(ref); chain != nil {
+		s.WriteString(chain[0])
+		for _, r := range chain[1:] {
+			fmt.Fprintf(&s, "\n  -> %s", r)
+		}
+		s.WriteByte('\n')
+	}
+	if s.Len() == 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%s is not included in the filtered spec\n", ref)
+		os.Exit(1)
+	}
+	fmt.Fprint(cmd.OutOrStdout(), s.String())
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal"
+	"github.com/zguydev/openapi-filter/internal/utils"
+	"github.com/zguydev/openapi-filter/pkg/config"
+	"github.com/zguydev/openapi-filter/pkg/filter"
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge output_spec [--config filter_config]",
+	Short: "Filter several specs and merge them into one",
+	Long: "Filter every spec listed under inputs in the config with its own filters " +
+		"and merge the filtered specs into a single output spec. Conflicting paths and " +
+		"components are resolved by the merge.conflicts policy: error, prefer-first or rename.",
+	Args: cobra.ExactArgs(1),
+	Run:  merge,
+}
+
+func init() {
+	addConfigFlags(mergeCmd.Flags())
+	rootCmd.AddCommand(mergeCmd)
+}
+
+func merge(cmd *cobra.Command, args []string) {
+	fallbackLogger := utils.NewFallbackLogger()
+	defer fallbackLogger.Sync() //nolint:errcheck
+
+	cfg, logger := setup(cmd, fallbackLogger)
+	outSpecPath := args[0]
+	if len(cfg.Inputs) == 0 {
+		logger.Fatal("no input specs to merge in config")
+	}
+	policy, err := filter.ParseConflictPolicy(cfg.Merge.Conflicts)
+	if err != nil {
+		logger.Fatal("invalid merge config", zap.Error(err))
+	}
+
+	inputs := make([]filter.MergeInput, 0, len(cfg.Inputs))
+	for _, input := range cfg.Inputs {
+		name := input.Name
+		if name == "" {
+			name = specName(input.Spec)
+		}
+		inputCfg := &config.Config{Tool: cfg.Tool, FilterConfig: input.FilterConfig}
+		oaf := filter.NewOpenAPISpecFilter(inputCfg, logger.With(zap.String("input", name)))
+		spec, err := oaf.Filter(loadInputSpec(cfg, logger, input.Spec))
+		if err != nil {
+			logger.Error("filter on spec failed", zap.Error(err), zap.String("path", input.Spec))
+			os.Exit(1)
+		}
+		inputs = append(inputs, filter.MergeInput{Name: name, Spec: spec})
+	}
+
+	outSpec, err := filter.Merge(logger, inputs, policy)
+	if err != nil {
+		logger.Error("merge of specs failed", zap.Error(err))
+		os.Exit(1)
+	}
+
+	if err := validateSpec(cmd.Context(), cfg.Tool.Validation, logger, outSpec); err != nil {
+		logger.Error("validation of spec failed", zap.Error(err))
+		os.Exit(1)
+	}
+
+	if err := internal.WriteSpecToFile(outSpec, outSpecPath); err != nil {
+		logger.Error("failed to write merged spec file",
+			zap.Error(err), zap.String("path", outSpecPath))
+		os.Exit(1)
+	}
+	logger.Info("merged and saved spec", zap.String("path", outSpecPath))
+}
+
+// specName returns the name of the spec file without extensions, e.g.
+// "billing" for "specs/billing.openapi.yaml".
+func specName(specPath string) string {
+	name, _, _ := strings.Cut(filepath.Base(specPath), ".")
+	return name
+}
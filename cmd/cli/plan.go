@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/utils"
+	"github.com/zguydev/openapi-filter/pkg/filter"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan input_spec [--config filter_config] [--format text|json]",
+	Short: "Print which paths, operations and components the filter would keep and drop",
+	Args:  cobra.ExactArgs(1),
+	Run:   plan,
+}
+
+func init() {
+	addConfigFlags(planCmd.Flags())
+	planCmd.Flags().String("format", formatText, "Output format: text or json")
+	rootCmd.AddCommand(planCmd)
+}
+
+func plan(cmd *cobra.Command, args []string) {
+	fallbackLogger := utils.NewFallbackLogger()
+	defer fallbackLogger.Sync() //nolint:errcheck
+
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		fallbackLogger.Fatal("failed to get format flag", zap.Error(err))
+	}
+	if format != formatText && format != formatJSON {
+		fallbackLogger.Fatal("unsupported output format", zap.String("format", format))
+	}
+
+	cfg, logger := setup(cmd, fallbackLogger, "stderr")
+	inputSpec := loadInputSpec(cfg, logger, args[0])
+
+	oaf := filter.NewOpenAPISpecFilter(cfg, logger)
+	if _, err := oaf.Filter(inputSpec); err != nil {
+		logger.Error("filter on spec failed", zap.Error(err))
+		os.Exit(1)
+	}
+
+	out := cmd.OutOrStdout()
+	report := oaf.Report()
+	if format == formatJSON {
+		err = writeJSON(out, report)
+	} else {
+		err = writePlanText(out, report)
+	}
+	if err != nil {
+		logger.Error("failed to write plan", zap.Error(err))
+		os.Exit(1)
+	}
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func writePlanText(w io.Writer, report *filter.Report) error {
+	var s strings.Builder
+	fmt.Fprintf(&s, "Kept operations (%d):\n", len(report.Operations))
+	for _, op := range report.Operations {
+		fmt.Fprintf(&s, "  + %s", formatOperation(op))
+		if op.NewPath != "" || op.NewOperationID != "" {
+			renamed := op
+			if op.NewPath != "" {
+				renamed.Path = op.NewPath
+			}
+			if op.NewOperationID != "" {
+				renamed.OperationID = op.NewOperationID
+			}
+			fmt.Fprintf(&s, " as %s", formatOperation(renamed))
+		}
+		s.WriteByte('\n')
+	}
+	fmt.Fprintf(&s, "\nKept components (%d):\n", len(report.Components))
+	for _, comp := range report.Components {
+		fmt.Fprintf(&s, "  + %s", comp.Ref)
+		if comp.NewRef != "" {
+			fmt.Fprintf(&s, " as %s", comp.NewRef)
+		}
+		var by []string
+		if comp.Selected {
+			by = append(by, "config")
+		}
+		by = append(by, comp.PulledBy...)
+		if len(by) > 0 {
+			fmt.Fprintf(&s, " <- %s", strings.Join(by, ", "))
+		}
+		if chain := comp.Chain; len(chain) > 2 {
+			fmt.Fprintf(&s, " (via %s)", strings.Join(chain[:len(chain)-1], " > "))
+		}
+		s.WriteByte('\n')
+	}
+	fmt.Fprintf(&s, "\nDropped paths (%d):\n", len(report.DroppedPaths))
+	for _, path := range report.DroppedPaths {
+		fmt.Fprintf(&s, "  - %s\n", path)
+	}
+	fmt.Fprintf(&s, "\nDropped operations (%d):\n", len(report.DroppedOperations))
+	for _, op := range report.DroppedOperations {
+		fmt.Fprintf(&s, "  - %s\n", formatOperation(op))
+	}
+	fmt.Fprintf(&s, "\nDropped components (%d):\n", len(report.DroppedComponents))
+	for _, comp := range report.DroppedComponents {
+		fmt.Fprintf(&s, "  - %s\n", comp.Ref)
+	}
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+func formatOperation(op filter.OperationReport) string {
+	s := filter.OperationSelector(op.Method, op.Path)
+	if op.OperationID != "" {
+		s += " (" + op.OperationID + ")"
+	}
+	return s
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var rootCmd = &cobra.Command{
@@ -17,6 +18,10 @@ func checkArgs(cmd *cobra.Command, args []string) error {
 	if ok, _ := cmd.Flags().GetBool("version"); ok {
 		return nil
 	}
+	if ok, _ := cmd.Flags().GetBool("dry-run"); ok {
+		const minArgs, maxArgs = 1, 2
+		return cobra.RangeArgs(minArgs, maxArgs)(cmd, args)
+	}
 	const exactArgs = 2
 	return cobra.ExactArgs(exactArgs)(cmd, args)
 }
@@ -27,7 +32,20 @@ func Execute() {
 	}
 }
 
+// addConfigFlags adds flags used to load the filter config.
+func addConfigFlags(flags *pflag.FlagSet) {
+	flags.String("config", "",
+		"Path to filter config (default: .openapi-filter.{yaml,yml,toml,json} "+
+			"in the working directory or its parents up to the repository root)")
+	flags.StringArray("set", nil,
+		"Override a config value as key=value (e.g. x-openapi-filter.logger.level=debug), can be repeated")
+}
+
 func init() {
-	rootCmd.Flags().String("config", ".openapi-filter.yaml", "Path to filter config")
+	addConfigFlags(rootCmd.Flags())
 	rootCmd.Flags().Bool("version", false, "Print version and exit")
+	rootCmd.Flags().Bool("dry-run", false, "Print what would be kept and dropped instead of writing the output spec")
+	rootCmd.Flags().String("format", formatText, "Dry-run output format: text or json")
+	rootCmd.Flags().Bool("check", false,
+		"Check that the output spec is up to date without writing it, exit with non-zero code if it is stale")
 }
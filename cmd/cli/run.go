@@ -1,15 +1,19 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	"github.com/zguydev/openapi-filter/internal"
 	"github.com/zguydev/openapi-filter/internal/utils"
 	"github.com/zguydev/openapi-filter/pkg/config"
+	"github.com/zguydev/openapi-filter/pkg/diff"
 	"github.com/zguydev/openapi-filter/pkg/filter"
 	"github.com/zguydev/openapi-filter/pkg/loader"
 )
@@ -24,30 +28,19 @@ func run(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	configPath, err := cmd.Flags().GetString("config")
+	dryRun, err := cmd.Flags().GetBool("dry-run")
 	if err != nil {
-		fallbackLogger.Fatal("failed to get config flag", zap.Error(err))
+		fallbackLogger.Fatal("failed to get dry-run flag", zap.Error(err))
 	}
-
-	cfg, err := config.LoadConfig(configPath)
-	if err != nil {
-		fallbackLogger.Fatal("failed to load config", zap.Error(err))
-	}
-
-	logger, err := utils.NewLogger(cfg.Tool.Logger)
-	if err != nil {
-		fallbackLogger.Fatal("failed to init logger", zap.Error(err))
+	if dryRun {
+		plan(cmd, args[:1])
+		return
 	}
 
+	cfg, logger := setup(cmd, fallbackLogger)
 	inputSpecPath, outSpecPath := args[0], args[1]
 
-	inputSpec, err := internal.LoadSpecFromFile(
-		loader.NewLoader(cfg.Tool.Loader), inputSpecPath)
-	if err != nil {
-		logger.Error("failed to load spec from file",
-			zap.Error(err), zap.String("path", inputSpecPath))
-		os.Exit(1)
-	}
+	inputSpec := loadInputSpec(cfg, logger, inputSpecPath)
 	oaf := filter.NewOpenAPISpecFilter(cfg, logger)
 	outSpec, err := oaf.Filter(inputSpec)
 	if err != nil {
@@ -55,6 +48,20 @@ func run(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if err := validateSpec(cmd.Context(), cfg.Tool.Validation, logger, outSpec); err != nil {
+		logger.Error("validation of spec failed", zap.Error(err))
+		os.Exit(1)
+	}
+
+	check, err := cmd.Flags().GetBool("check")
+	if err != nil {
+		logger.Fatal("failed to get check flag", zap.Error(err))
+	}
+	if check {
+		checkSpec(cmd, logger, outSpec, outSpecPath)
+		return
+	}
+
 	if err := internal.WriteSpecToFile(outSpec, outSpecPath); err != nil {
 		logger.Error("failed to write filtered spec file",
 			zap.Error(err), zap.String("path", outSpecPath))
@@ -62,3 +69,128 @@ func run(cmd *cobra.Command, args []string) {
 	}
 	logger.Info("filtered and saved spec", zap.String("path", outSpecPath))
 }
+
+// checkSpec compares the filtered spec semantically with the existing
+// output spec file without writing anything. Exits with the list of
+// differences if the output spec is stale.
+func checkSpec(cmd *cobra.Command, logger *zap.Logger, spec *openapi3.T, outSpecPath string) {
+	data, err := internal.EncodeSpec(spec)
+	if err != nil {
+		logger.Fatal("failed to encode filtered spec", zap.Error(err))
+	}
+	expected, err := internal.DecodeSpec(data)
+	if err != nil {
+		logger.Fatal("failed to decode filtered spec", zap.Error(err))
+	}
+	actual, err := internal.DecodeSpecFile(outSpecPath)
+	if err != nil {
+		logger.Error("failed to read output spec file",
+			zap.Error(err), zap.String("path", outSpecPath))
+		os.Exit(1)
+	}
+
+	changes := diff.Values(actual, expected)
+	if len(changes) == 0 {
+		logger.Info("output spec is up to date", zap.String("path", outSpecPath))
+		return
+	}
+	out := cmd.ErrOrStderr()
+	fmt.Fprintf(out, "%s is stale, changes required to bring it up to date (%d):\n",
+		outSpecPath, len(changes))
+	for _, change := range changes {
+		fmt.Fprintf(out, "  %s\n", change)
+	}
+	os.Exit(1)
+}
+
+var errInvalidSpec = errors.New("filtered spec is invalid")
+
+// validateSpec validates the filtered spec if enabled in the config and
+// logs violations. Returns [errInvalidSpec] on violations unless they are
+// configured to be warnings.
+func validateSpec(
+	ctx context.Context,
+	cfg *config.ValidationConfig,
+	logger *zap.Logger,
+	spec *openapi3.T,
+) error {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	violations := filter.Validate(ctx, spec)
+	for _, violation := range violations {
+		if cfg.WarnOnly {
+			logger.Warn("filtered spec validation", zap.Error(violation))
+		} else {
+			logger.Error("filtered spec validation", zap.Error(violation))
+		}
+	}
+	if len(violations) > 0 && !cfg.WarnOnly {
+		return fmt.Errorf("%w: %d violations", errInvalidSpec, len(violations))
+	}
+	return nil
+}
+
+// setup loads the config using the config flags of cmd and creates the
+// logger writing to logOutputs (stdout by default). Exits on failure.
+func setup(
+	cmd *cobra.Command,
+	fallbackLogger *zap.Logger,
+	logOutputs ...string,
+) (*config.Config, *zap.Logger) {
+	configPath, err := cmd.Flags().GetString("config")
+	if err != nil {
+		fallbackLogger.Fatal("failed to get config flag", zap.Error(err))
+	}
+	overrides, err := cmd.Flags().GetStringArray("set")
+	if err != nil {
+		fallbackLogger.Fatal("failed to get set flag", zap.Error(err))
+	}
+
+	var unknownEnv []string
+	cfg, err := loadConfig(configPath,
+		config.WithEnv(config.EnvPrefix),
+		config.WithUnknownEnvHandler(func(names []string) { unknownEnv = names }),
+		config.WithOverrides(overrides...))
+	if err != nil {
+		fallbackLogger.Fatal("failed to load config", zap.Error(err))
+	}
+
+	logger, err := utils.NewLogger(cfg.Tool.Logger, logOutputs...)
+	if err != nil {
+		fallbackLogger.Fatal("failed to init logger", zap.Error(err))
+	}
+	if len(unknownEnv) > 0 {
+		logger.Warn("ignored environment variables matching no config key",
+			zap.Strings("names", unknownEnv))
+	}
+	return cfg, logger
+}
+
+// loadInputSpec loads the spec to filter. Exits on failure.
+func loadInputSpec(cfg *config.Config, logger *zap.Logger, specPath string) *openapi3.T {
+	spec, err := internal.LoadSpecFromFile(loader.NewLoader(cfg.Tool.Loader), specPath)
+	if err != nil {
+		logger.Error("failed to load spec from file",
+			zap.Error(err), zap.String("path", specPath))
+		os.Exit(1)
+	}
+	return spec
+}
+
+// loadConfig loads the config from configPath. If configPath is empty,
+// the config is discovered from the working directory, and if there is
+// none, the default pass-through config is used.
+func loadConfig(configPath string, opts ...config.LoadOption) (*config.Config, error) {
+	if configPath == "" {
+		var err error
+		configPath, err = config.FindConfig(".")
+		if errors.Is(err, config.ErrConfigNotFound) {
+			return config.LoadDefaultConfig(opts...)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("config.FindConfig: %w", err)
+		}
+	}
+	return config.LoadConfig(configPath, opts...)
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+const danglingSpec = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+components:
+  schemas:
+    Pet: {type: object}
+`
+
+func TestValidateSpec(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *config.ValidationConfig
+		wantErr   error
+		wantLevel zapcore.Level
+		wantLogs  int
+	}{
+		{name: "disabled", cfg: &config.ValidationConfig{}},
+		{name: "not configured"},
+		{name: "error", cfg: &config.ValidationConfig{Enabled: true}, wantErr: errInvalidSpec, wantLevel: zapcore.ErrorLevel, wantLogs: 1},
+		{name: "warn only", cfg: &config.ValidationConfig{Enabled: true, WarnOnly: true}, wantLevel: zapcore.WarnLevel, wantLogs: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := openapi3.NewLoader().LoadFromData([]byte(danglingSpec))
+			if err != nil {
+				t.Fatalf("LoadFromData() error = %v", err)
+			}
+			// Filtering out a referenced component leaves a dangling ref.
+			delete(spec.Components.Schemas, "Pet")
+
+			core, logs := observer.New(zapcore.DebugLevel)
+			err = validateSpec(context.Background(), tt.cfg, zap.New(core), spec)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateSpec() error = %v, want %v", err, tt.wantErr)
+			}
+			entries := logs.All()
+			if len(entries) != tt.wantLogs {
+				t.Fatalf("logged %d entries, want %d", len(entries), tt.wantLogs)
+			}
+			for _, entry := range entries {
+				if entry.Level != tt.wantLevel {
+					t.Errorf("logged %q at %v, want %v", entry.Message, entry.Level, tt.wantLevel)
+				}
+				if got, want := entry.ContextMap()["error"], `dangling ref "#/components/schemas/Pet" referenced from /pets`; got != want {
+					t.Errorf("logged error %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
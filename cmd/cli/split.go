@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal"
+	"github.com/zguydev/openapi-filter/internal/utils"
+	"github.com/zguydev/openapi-filter/pkg/filter"
+)
+
+const splitIndexFile = "index.yaml"
+
+var splitCmd = &cobra.Command{
+	Use:   "split input_spec output_dir [--by tag|path] [--config filter_config]",
+	Short: "Split a spec into one filtered spec per tag or first path segment",
+	Long: "Write one filtered spec per operation tag or first path segment to output_dir, " +
+		"each with the components it references, and an index.yaml listing them. " +
+		"Filters of the config other than paths and select apply to every spec.",
+	Args: cobra.ExactArgs(2),
+	Run:  split,
+}
+
+func init() {
+	addConfigFlags(splitCmd.Flags())
+	splitCmd.Flags().String("by", string(filter.SplitByTag), "Group operations by: tag or path")
+	rootCmd.AddCommand(splitCmd)
+}
+
+// splitIndex lists the specs written by the split command.
+type splitIndex struct {
+	Specs []splitIndexEntry `yaml:"specs"`
+}
+
+type splitIndexEntry struct {
+	Name       string `yaml:"name"`       // Tag or first path segment
+	Spec       string `yaml:"spec"`       // Spec file name
+	Operations int    `yaml:"operations"` // Number of operations
+}
+
+var unsafeFileNameRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func split(cmd *cobra.Command, args []string) {
+	fallbackLogger := utils.NewFallbackLogger()
+	defer fallbackLogger.Sync() //nolint:errcheck
+
+	by, err := cmd.Flags().GetString("by")
+	if err != nil {
+		fallbackLogger.Fatal("failed to get by flag", zap.Error(err))
+	}
+
+	cfg, logger := setup(cmd, fallbackLogger)
+	inputSpecPath, outDir := args[0], args[1]
+
+	inputSpec := loadInputSpec(cfg, logger, inputSpecPath)
+	groups, err := filter.SplitPaths(inputSpec, filter.SplitBy(by))
+	if err != nil {
+		logger.Fatal("failed to split spec", zap.Error(err))
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil { //nolint:gosec
+		logger.Fatal("failed to create output directory", zap.Error(err), zap.String("path", outDir))
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	index := splitIndex{Specs: make([]splitIndexEntry, 0, len(names))}
+	files := make(map[string]string) // File name -> group name
+	for _, name := range names {
+		fileName := unsafeFileNameRegexp.ReplaceAllString(name, "-") + ".openapi.yaml"
+		if other, ok := files[fileName]; ok {
+			logger.Fatal("groups have the same spec file name",
+				zap.String("group", name), zap.String("other", other), zap.String("file", fileName))
+		}
+		files[fileName] = name
+
+		groupCfg := *cfg
+		groupCfg.PassThrough = false
+		groupCfg.Paths = groups[name]
+		groupCfg.Select = nil
+		oaf := filter.NewOpenAPISpecFilter(&groupCfg, logger.With(zap.String("group", name)))
+		outSpec, err := oaf.Filter(inputSpec)
+		if err != nil {
+			logger.Error("filter on spec failed", zap.Error(err), zap.String("group", name))
+			os.Exit(1)
+		}
+
+		outSpecPath := filepath.Join(outDir, fileName)
+		if err := internal.WriteSpecToFile(outSpec, outSpecPath); err != nil {
+			logger.Error("failed to write filtered spec file",
+				zap.Error(err), zap.String("path", outSpecPath))
+			os.Exit(1)
+		}
+		var operations int
+		for _, methods := range groups[name] {
+			operations += len(methods)
+		}
+		index.Specs = append(index.Specs, splitIndexEntry{
+			Name:       name,
+			Spec:       fileName,
+			Operations: operations,
+		})
+	}
+
+	indexPath := filepath.Join(outDir, splitIndexFile)
+	if err := internal.WriteYAMLToFile(index, indexPath); err != nil {
+		logger.Error("failed to write index file", zap.Error(err), zap.String("path", indexPath))
+		os.Exit(1)
+	}
+	logger.Info("split and saved specs", zap.Int("specs", len(index.Specs)), zap.String("path", outDir))
+}
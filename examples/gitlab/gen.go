@@ -1,3 +1,3 @@
 package dockerhub_example
 
-//go:generate go run github.com/zguydev/openapi-filter openapi.yaml filtered.openapi.yaml --config .openapi-filter.toml
+//go:generate go run github.com/zguydev/openapi-filter openapi.yaml filtered.openapi.yaml
@@ -125,3 +125,33 @@ func ComponentTypeToCfgNames(
 		panic(fmt.Errorf("unsupported component type: %T", typ))
 	}
 }
+
+// AddCfgNames adds component names of the given type to the config.
+func AddCfgNames(
+	cfg *config.FilterComponentsConfig,
+	typ ComponentType,
+	names ...string,
+) {
+	switch typ {
+	case ComponentTypeSchema:
+		cfg.Schemas = append(cfg.Schemas, names...)
+	case ComponentTypeParameter:
+		cfg.Parameters = append(cfg.Parameters, names...)
+	case ComponentTypeHeader:
+		cfg.Headers = append(cfg.Headers, names...)
+	case ComponentTypeRequestBody:
+		cfg.RequestBodies = append(cfg.RequestBodies, names...)
+	case ComponentTypeResponse:
+		cfg.Responses = append(cfg.Responses, names...)
+	case ContentTypeSecuritySchema:
+		cfg.SecuritySchemes = append(cfg.SecuritySchemes, names...)
+	case ContentTypeExample:
+		cfg.Examples = append(cfg.Examples, names...)
+	case ContentTypeLink:
+		cfg.Links = append(cfg.Links, names...)
+	case ContentTypeCallback:
+		cfg.Callbacks = append(cfg.Callbacks, names...)
+	default:
+		panic(fmt.Errorf("unsupported component type: %T", typ))
+	}
+}
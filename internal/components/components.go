@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/getkin/kin-openapi/openapi3"
 )
@@ -16,10 +17,10 @@ func initializeComponentMap[M ~map[string]V, V any](compMap *M) (inited bool) {
 
 func copyComponent[M ~map[string]V, V any](
 	docCompMap, filteredCompMap M,
-	name string,
+	name, newName string,
 ) (ok bool) {
 	if component, ok := docCompMap[name]; ok {
-		filteredCompMap[name] = component
+		filteredCompMap[newName] = component
 		return true
 	}
 	return false
@@ -57,40 +58,50 @@ func setComponentMapInComponents[T any](
 func processCopyComponentByType[T any](
 	docComps, filteredComps *openapi3.Components,
 	typ ComponentType,
-	name string,
+	name, newName string,
 ) (ok bool) {
 	docCompMap := ComponentTypeToComponentMap[T](docComps, typ)
 	filteredCompMap := ComponentTypeToComponentMap[T](filteredComps, typ)
 	if initializeComponentMap(&filteredCompMap) {
 		setComponentMapInComponents(filteredComps, typ, filteredCompMap)
 	}
-	return copyComponent(docCompMap, filteredCompMap, name)
+	return copyComponent(docCompMap, filteredCompMap, name, newName)
 }
 
 func ProcessCopyComponent(
 	docComps, filteredComps *openapi3.Components,
 	typ ComponentType,
 	name string,
+) (ok bool) {
+	return ProcessCopyComponentAs(docComps, filteredComps, typ, name, name)
+}
+
+// ProcessCopyComponentAs copies the component to the filtered components
+// under a new name. Refs to the component are not rewritten.
+func ProcessCopyComponentAs(
+	docComps, filteredComps *openapi3.Components,
+	typ ComponentType,
+	name, newName string,
 ) (ok bool) {
 	switch typ {
 	case ComponentTypeSchema:
-		return processCopyComponentByType[*openapi3.SchemaRef](docComps, filteredComps, typ, name)
+		return processCopyComponentByType[*openapi3.SchemaRef](docComps, filteredComps, typ, name, newName)
 	case ComponentTypeParameter:
-		return processCopyComponentByType[*openapi3.ParameterRef](docComps, filteredComps, typ, name)
+		return processCopyComponentByType[*openapi3.ParameterRef](docComps, filteredComps, typ, name, newName)
 	case ComponentTypeHeader:
-		return processCopyComponentByType[*openapi3.HeaderRef](docComps, filteredComps, typ, name)
+		return processCopyComponentByType[*openapi3.HeaderRef](docComps, filteredComps, typ, name, newName)
 	case ComponentTypeRequestBody:
-		return processCopyComponentByType[*openapi3.RequestBodyRef](docComps, filteredComps, typ, name)
+		return processCopyComponentByType[*openapi3.RequestBodyRef](docComps, filteredComps, typ, name, newName)
 	case ComponentTypeResponse:
-		return processCopyComponentByType[*openapi3.ResponseRef](docComps, filteredComps, typ, name)
+		return processCopyComponentByType[*openapi3.ResponseRef](docComps, filteredComps, typ, name, newName)
 	case ContentTypeSecuritySchema:
-		return processCopyComponentByType[*openapi3.SecuritySchemeRef](docComps, filteredComps, typ, name)
+		return processCopyComponentByType[*openapi3.SecuritySchemeRef](docComps, filteredComps, typ, name, newName)
 	case ContentTypeExample:
-		return processCopyComponentByType[*openapi3.ExampleRef](docComps, filteredComps, typ, name)
+		return processCopyComponentByType[*openapi3.ExampleRef](docComps, filteredComps, typ, name, newName)
 	case ContentTypeLink:
-		return processCopyComponentByType[*openapi3.LinkRef](docComps, filteredComps, typ, name)
+		return processCopyComponentByType[*openapi3.LinkRef](docComps, filteredComps, typ, name, newName)
 	case ContentTypeCallback:
-		return processCopyComponentByType[*openapi3.CallbackRef](docComps, filteredComps, typ, name)
+		return processCopyComponentByType[*openapi3.CallbackRef](docComps, filteredComps, typ, name, newName)
 	default:
 		panic(fmt.Errorf("unsupported component type: %v", typ))
 	}
@@ -102,28 +113,30 @@ func isComponentMapEmpty(
 ) bool {
 	switch typ {
 	case ComponentTypeSchema:
-		return len(comps.Schemas) != 0
+		return len(comps.Schemas) == 0
 	case ComponentTypeParameter:
-		return len(comps.Parameters) != 0
+		return len(comps.Parameters) == 0
 	case ComponentTypeHeader:
-		return len(comps.Headers) != 0
+		return len(comps.Headers) == 0
 	case ComponentTypeRequestBody:
-		return len(comps.RequestBodies) != 0
+		return len(comps.RequestBodies) == 0
 	case ComponentTypeResponse:
-		return len(comps.Responses) != 0
+		return len(comps.Responses) == 0
 	case ContentTypeSecuritySchema:
-		return len(comps.SecuritySchemes) != 0
+		return len(comps.SecuritySchemes) == 0
 	case ContentTypeExample:
-		return len(comps.Examples) != 0
+		return len(comps.Examples) == 0
 	case ContentTypeLink:
-		return len(comps.Links) != 0
+		return len(comps.Links) == 0
 	case ContentTypeCallback:
-		return len(comps.Callbacks) != 0
+		return len(comps.Callbacks) == 0
 	default:
 		panic(fmt.Errorf("unsupported component type: %v", typ))
 	}
 }
 
+// IsEmptyComponents reports whether comps is nil or has no components of
+// any type. Component extensions are not taken into account.
 func IsEmptyComponents(comps *openapi3.Components) bool {
 	if comps == nil {
 		return true
@@ -135,3 +148,71 @@ func IsEmptyComponents(comps *openapi3.Components) bool {
 	}
 	return true
 }
+
+func componentNames[M ~map[string]V, V any](compMap M) []string {
+	names := make([]string, 0, len(compMap))
+	for name := range compMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ComponentNames returns sorted names of components of the given type.
+func ComponentNames(comps *openapi3.Components, typ ComponentType) []string {
+	switch typ {
+	case ComponentTypeSchema:
+		return componentNames(comps.Schemas)
+	case ComponentTypeParameter:
+		return componentNames(comps.Parameters)
+	case ComponentTypeHeader:
+		return componentNames(comps.Headers)
+	case ComponentTypeRequestBody:
+		return componentNames(comps.RequestBodies)
+	case ComponentTypeResponse:
+		return componentNames(comps.Responses)
+	case ContentTypeSecuritySchema:
+		return componentNames(comps.SecuritySchemes)
+	case ContentTypeExample:
+		return componentNames(comps.Examples)
+	case ContentTypeLink:
+		return componentNames(comps.Links)
+	case ContentTypeCallback:
+		return componentNames(comps.Callbacks)
+	default:
+		panic(fmt.Errorf("unsupported component type: %v", typ))
+	}
+}
+
+// ComponentValue returns the component of the given type and name, e.g.
+// *openapi3.SchemaRef, or nil if there is none.
+func ComponentValue(comps *openapi3.Components, typ ComponentType, name string) any {
+	var value any
+	var ok bool
+	switch typ {
+	case ComponentTypeSchema:
+		value, ok = comps.Schemas[name]
+	case ComponentTypeParameter:
+		value, ok = comps.Parameters[name]
+	case ComponentTypeHeader:
+		value, ok = comps.Headers[name]
+	case ComponentTypeRequestBody:
+		value, ok = comps.RequestBodies[name]
+	case ComponentTypeResponse:
+		value, ok = comps.Responses[name]
+	case ContentTypeSecuritySchema:
+		value, ok = comps.SecuritySchemes[name]
+	case ContentTypeExample:
+		value, ok = comps.Examples[name]
+	case ContentTypeLink:
+		value, ok = comps.Links[name]
+	case ContentTypeCallback:
+		value, ok = comps.Callbacks[name]
+	default:
+		panic(fmt.Errorf("unsupported component type: %v", typ))
+	}
+	if !ok {
+		return nil
+	}
+	return value
+}
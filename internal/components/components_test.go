@@ -0,0 +1,38 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestIsEmptyComponents(t *testing.T) {
+	tests := []struct {
+		name  string
+		comps *openapi3.Components
+		want  bool
+	}{
+		{name: "nil", comps: nil, want: true},
+		{name: "no components", comps: &openapi3.Components{}, want: true},
+		{name: "empty maps", comps: &openapi3.Components{
+			Schemas:   openapi3.Schemas{},
+			Callbacks: openapi3.Callbacks{},
+		}, want: true},
+		{name: "schema", comps: &openapi3.Components{
+			Schemas: openapi3.Schemas{"Pet": &openapi3.SchemaRef{Value: &openapi3.Schema{}}},
+		}, want: false},
+		{name: "security scheme only", comps: &openapi3.Components{
+			SecuritySchemes: openapi3.SecuritySchemes{"api_key": &openapi3.SecuritySchemeRef{}},
+		}, want: false},
+		{name: "callback only", comps: &openapi3.Components{
+			Callbacks: openapi3.Callbacks{"onEvent": &openapi3.CallbackRef{}},
+		}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsEmptyComponents(tt.comps); got != tt.want {
+				t.Errorf("IsEmptyComponents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
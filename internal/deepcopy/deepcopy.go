@@ -0,0 +1,107 @@
+// Package deepcopy provides deep copying of the OpenAPI document model.
+package deepcopy
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Copy returns a deep copy of v, including unexported fields. Pointers and
+// maps shared within v are shared within the copy as well, so circular
+// refs (e.g. of recursive schemas) are copied as is.
+func Copy[T any](v T) T {
+	c := copier{copies: make(map[copyKey]reflect.Value)}
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	c.copy(dst, src)
+	return dst.Interface().(T)
+}
+
+type copyKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// copier memoizes copies of pointers and maps by their addresses.
+type copier struct {
+	copies map[copyKey]reflect.Value
+}
+
+// copy sets dst, which must be settable, to a deep copy of src.
+func (c *copier) copy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		key := copyKey{ptr: src.Pointer(), typ: src.Type()}
+		if copied, ok := c.copies[key]; ok {
+			dst.Set(copied)
+			return
+		}
+		copied := reflect.New(src.Type().Elem())
+		c.copies[key] = copied
+		c.copy(copied.Elem(), src.Elem())
+		dst.Set(copied)
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		key := copyKey{ptr: src.Pointer(), typ: src.Type()}
+		if copied, ok := c.copies[key]; ok {
+			dst.Set(copied)
+			return
+		}
+		copied := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.copies[key] = copied
+		iter := src.MapRange()
+		for iter.Next() {
+			k := reflect.New(src.Type().Key()).Elem()
+			c.copy(k, iter.Key())
+			v := reflect.New(src.Type().Elem()).Elem()
+			c.copy(v, iter.Value())
+			copied.SetMapIndex(k, v)
+		}
+		dst.Set(copied)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		copied := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := range src.Len() {
+			c.copy(copied.Index(i), src.Index(i))
+		}
+		dst.Set(copied)
+	case reflect.Array:
+		for i := range src.Len() {
+			c.copy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		copied := reflect.New(src.Elem().Type()).Elem()
+		c.copy(copied, src.Elem())
+		dst.Set(copied)
+	case reflect.Struct:
+		if !src.CanAddr() {
+			addressable := reflect.New(src.Type()).Elem()
+			addressable.Set(src)
+			src = addressable
+		}
+		for i := range src.NumField() {
+			c.copy(accessible(dst.Field(i)), accessible(src.Field(i)))
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+// accessible makes an unexported struct field of an addressable struct
+// readable and settable.
+func accessible(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem() //nolint:gosec
+}
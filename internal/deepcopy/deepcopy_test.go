@@ -0,0 +1,156 @@
+package deepcopy
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+x-owner: {team: pets, tags: [a, b]}
+paths:
+  /pets:
+    get:
+      x-internal: true
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+  /pets/{id}:
+    get:
+      parameters: [{name: id, in: path, required: true, schema: {type: string}}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+        parent: {$ref: "#/components/schemas/Pet"}
+`
+
+func loadSpec(t *testing.T) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatalf("LoadFromData() error = %v", err)
+	}
+	return doc
+}
+
+func TestCopy(t *testing.T) {
+	doc := loadSpec(t)
+	want, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	copied := Copy(doc)
+	got, err := json.Marshal(copied)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("Copy() = %s, want %s", got, want)
+	}
+
+	// Changes of the copy, including unexported maps and extensions, must
+	// not change the source.
+	copied.Paths.Delete("/pets")
+	copied.Paths.Value("/pets/{id}").Get.Parameters[0].Value.Name = "petId"
+	copied.Components.Schemas["Pet"].Value.Properties["name"].Value.Type = &openapi3.Types{"integer"}
+	copied.Extensions["x-owner"].(map[string]any)["team"] = "animals"
+	copied.Extensions["x-owner"].(map[string]any)["tags"].([]any)[0] = "c"
+	if after, _ := json.Marshal(doc); string(after) != string(want) {
+		t.Errorf("source changed by changing the copy: %s, want %s", after, want)
+	}
+}
+
+func TestCopySharesNoMemory(t *testing.T) {
+	doc := loadSpec(t)
+	copied := Copy(doc)
+
+	source := make(map[uintptr]reflect.Type)
+	addresses(reflect.ValueOf(doc), source)
+	copies := make(map[uintptr]reflect.Type)
+	addresses(reflect.ValueOf(copied), copies)
+	for addr, typ := range copies {
+		if source[addr] == typ {
+			t.Errorf("copy shares a %v with the source", typ)
+		}
+	}
+}
+
+func TestCopyKeepsSharing(t *testing.T) {
+	doc := loadSpec(t)
+	copied := Copy(doc)
+
+	pet := copied.Components.Schemas["Pet"].Value
+	if pet == doc.Components.Schemas["Pet"].Value {
+		t.Fatal("Copy() shares the Pet schema with the source")
+	}
+	// The recursive schema refers to its copy, not to the source.
+	if parent := pet.Properties["parent"].Value; parent != pet {
+		t.Errorf("recursive ref of the copy = %p, want %p", parent, pet)
+	}
+	// Refs to the same component share its copy.
+	for _, path := range []string{"/pets", "/pets/{id}"} {
+		schema := copied.Paths.Value(path).Get.Responses.Status(200).Value.Content.Get("application/json").Schema
+		if schema.Value != pet {
+			t.Errorf("%s: ref target = %p, want %p", path, schema.Value, pet)
+		}
+	}
+}
+
+// addresses collects the addresses of pointers and maps reachable from v.
+func addresses(v reflect.Value, seen map[uintptr]reflect.Type) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		if _, ok := seen[v.Pointer()]; ok {
+			return
+		}
+		seen[v.Pointer()] = v.Type()
+		if v.Kind() == reflect.Pointer {
+			addresses(v.Elem(), seen)
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			addresses(iter.Key(), seen)
+			addresses(iter.Value(), seen)
+		}
+	case reflect.Slice:
+		if v.Len() > 0 {
+			seen[v.Pointer()] = v.Type()
+		}
+		for i := range v.Len() {
+			addresses(v.Index(i), seen)
+		}
+	case reflect.Array:
+		for i := range v.Len() {
+			addresses(v.Index(i), seen)
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			addresses(v.Elem(), seen)
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			addresses(v.Field(i), seen)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package expr
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+type node interface {
+	eval(vars map[string]any) (any, error)
+}
+
+type literalNode struct {
+	v any
+}
+
+func (n literalNode) eval(map[string]any) (any, error) {
+	return n.v, nil
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n variableNode) eval(vars map[string]any) (any, error) {
+	return normalize(vars[n.name]), nil
+}
+
+type listNode struct {
+	items []node
+}
+
+func (n listNode) eval(vars map[string]any) (any, error) {
+	list := make([]any, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+type indexNode struct {
+	x   node
+	key node
+}
+
+func (n indexNode) eval(vars map[string]any) (any, error) {
+	x, err := n.x.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	key, err := n.key.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch x := x.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		k, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key is %s, not a string", typeName(key))
+		}
+		return normalize(x[k]), nil
+	default:
+		return nil, fmt.Errorf("can not index %s", typeName(x))
+	}
+}
+
+type notNode struct {
+	x node
+}
+
+func (n notNode) eval(vars map[string]any) (any, error) {
+	b, err := evalBool(n.x, vars, "!")
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+type logicalNode struct {
+	and   bool
+	left  node
+	right node
+}
+
+func (n logicalNode) eval(vars map[string]any) (any, error) {
+	op := "||"
+	if n.and {
+		op = "&&"
+	}
+	left, err := evalBool(n.left, vars, op)
+	if err != nil {
+		return nil, err
+	}
+	if left != n.and {
+		return left, nil
+	}
+	return evalBool(n.right, vars, op)
+}
+
+type comparisonNode struct {
+	op    string
+	left  node
+	right node
+}
+
+func (n comparisonNode) eval(vars map[string]any) (any, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return reflect.DeepEqual(left, right), nil
+	case "!=":
+		return !reflect.DeepEqual(left, right), nil
+	case "in":
+		return in(left, right)
+	case "contains":
+		return in(right, left)
+	}
+	s, okLeft := left.(string)
+	prefix, okRight := right.(string)
+	if !okLeft || !okRight {
+		return nil, fmt.Errorf("%s takes strings, got %s and %s", n.op, typeName(left), typeName(right))
+	}
+	if n.op == "startsWith" {
+		return strings.HasPrefix(s, prefix), nil
+	}
+	return strings.HasSuffix(s, prefix), nil
+}
+
+type matchNode struct {
+	x  node
+	re *regexp.Regexp
+}
+
+func (n matchNode) eval(vars map[string]any) (any, error) {
+	x, err := n.x.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := x.(string)
+	if !ok {
+		return nil, fmt.Errorf("matches takes a string, got %s", typeName(x))
+	}
+	return n.re.MatchString(s), nil
+}
+
+// in reports whether v is an element of a list, a key of a map or a
+// substring of a string.
+func in(v, container any) (bool, error) {
+	switch container := container.(type) {
+	case []any:
+		for _, item := range container {
+			if reflect.DeepEqual(v, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		key, ok := v.(string)
+		if !ok {
+			return false, fmt.Errorf("map key is %s, not a string", typeName(v))
+		}
+		_, ok = container[key]
+		return ok, nil
+	case string:
+		s, ok := v.(string)
+		if !ok {
+			return false, fmt.Errorf("substring is %s, not a string", typeName(v))
+		}
+		return strings.Contains(container, s), nil
+	default:
+		return false, fmt.Errorf("in takes a list, map or string, got %s", typeName(container))
+	}
+}
+
+func evalBool(n node, vars map[string]any, op string) (bool, error) {
+	v, err := n.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("operand of %s is %s, not a boolean", op, typeName(v))
+	}
+	return b, nil
+}
+
+// normalize converts values of variables to the types of literals:
+// numbers to float64, and lists and maps of other element types to
+// []any and map[string]any.
+func normalize(v any) any {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Slice:
+		list := make([]any, rv.Len())
+		for i := range list {
+			list[i] = normalize(rv.Index(i).Interface())
+		}
+		return list
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v
+		}
+		m := make(map[string]any, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return m
+	case reflect.Invalid:
+		return nil
+	default:
+		return v
+	}
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case string:
+		return "a string"
+	case float64:
+		return "a number"
+	case []any:
+		return "a list"
+	case map[string]any:
+		return "a map"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,267 @@
+// Package expr implements a small expression language for predicates
+// over named variables, e.g.
+//
+//	method == "GET" && "public" in tags && !(path contains "/admin")
+//
+// Expressions consist of:
+//   - literals: strings ("a" or 'a'), numbers, true, false, null and
+//     lists (["a", "b"]);
+//   - variables, and map values by key (extensions["x-beta"], null if
+//     the key is missing);
+//   - comparisons: ==, !=, in (list element, map key or substring),
+//     contains (reverse of in), startsWith, endsWith and matches (RE2
+//     regular expression literal);
+//   - logical operators: ! (not), && (and), || (or), and parentheses.
+//
+// Logical operators take only booleans, so a misspelled or missing
+// value fails the evaluation instead of silently not matching.
+package expr
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// Expr is a compiled expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile parses the expression. Variables not listed in vars are
+// rejected, so that typos are reported before evaluation.
+func Compile(src string, vars []string) (*Expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, vars: vars}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval evaluates the expression with the values of variables. The
+// expression must evaluate to a boolean.
+func (e *Expr) Eval(vars map[string]any) (bool, error) {
+	v, err := e.root.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("result is %s, not a boolean", typeName(v))
+	}
+	return b, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	vars   []string
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the punctuation tokens
+// or keywords.
+func (p *parser) accept(texts ...string) (string, bool) {
+	t := p.peek()
+	if (t.kind == tokenPunct || t.kind == tokenIdent) && slices.Contains(texts, t.text) {
+		p.next()
+		return t.text, true
+	}
+	return "", false
+}
+
+func (p *parser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		t := p.peek()
+		return fmt.Errorf("expected %q at %d, got %q", text, t.pos, t.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{and: false, left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{and: true, left: left, right: right}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	op, ok := p.accept("==", "!=", "in", "contains", "startsWith", "endsWith", "matches")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if op == "matches" {
+		lit, ok := right.(literalNode)
+		pattern, isString := lit.v.(string)
+		if !ok || !isString {
+			return nil, fmt.Errorf("matches at %d takes a string literal", t.pos)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("matches at %d: %w", t.pos, err)
+		}
+		return matchNode{x: left, re: re}, nil
+	}
+	return comparisonNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	var x node
+	switch t.kind {
+	case tokenString:
+		x = literalNode{v: t.text}
+	case tokenNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at %d", t.text, t.pos)
+		}
+		x = literalNode{v: n}
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			x = literalNode{v: true}
+		case "false":
+			x = literalNode{v: false}
+		case "null":
+			x = literalNode{v: nil}
+		default:
+			if !slices.Contains(p.vars, t.text) {
+				return nil, fmt.Errorf("unknown variable %q at %d", t.text, t.pos)
+			}
+			x = variableNode{name: t.text}
+		}
+	case tokenPunct:
+		switch t.text {
+		case "(":
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			x = inner
+		case "[":
+			list, err := p.parseList()
+			if err != nil {
+				return nil, err
+			}
+			x = list
+		}
+	}
+	if x == nil {
+		if t.kind == tokenEOF {
+			return nil, fmt.Errorf("unexpected end of expression at %d", t.pos)
+		}
+		return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+	}
+
+	for {
+		if _, ok := p.accept("["); !ok {
+			return x, nil
+		}
+		key, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		x = indexNode{x: x, key: key}
+	}
+}
+
+// parseList parses list items after the opening bracket.
+func (p *parser) parseList() (node, error) {
+	var list listNode
+	if _, ok := p.accept("]"); ok {
+		return list, nil
+	}
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		list.items = append(list.items, item)
+		if _, ok := p.accept("]"); ok {
+			return list, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+)
+
+var testVars = []string{"method", "path", "tags", "operationId", "extensions", "deprecated"}
+
+func testValues() map[string]any {
+	return map[string]any{
+		"method":      "GET",
+		"path":        "/admin/users",
+		"tags":        []string{"public", "users"},
+		"operationId": "listUsers",
+		"extensions":  map[string]any{"x-beta": true, "x-rank": 2, "x-meta": map[string]any{"team": "core"}},
+		"deprecated":  false,
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		// Literals and comparisons.
+		{`true`, true},
+		{`method == "GET"`, true},
+		{`method == 'POST'`, false},
+		{`method != "POST"`, true},
+		{`deprecated == false`, true},
+		{`extensions["x-rank"] == 2`, true},
+		{`extensions["x-missing"] == null`, true},
+		{`extensions["x-meta"]["team"] == "core"`, true},
+		{`extensions["x-missing"]["team"] == null`, true},
+		{`tags == ["public", "users"]`, true},
+
+		// Precedence: ! binds tighter than &&, && tighter than ||.
+		{`true || false && false`, true},
+		{`(true || false) && false`, false},
+		{`!false && false`, false},
+		{`!(false && false)`, true},
+		{`false || !deprecated`, true},
+		{`not deprecated and method == "GET" or false`, true},
+		{`!!true`, true},
+
+		// in and contains on lists, maps and strings.
+		{`"public" in tags`, true},
+		{`"internal" in tags`, false},
+		{`"GET" in ["GET", "HEAD"]`, true},
+		{`"x-beta" in extensions`, true},
+		{`"x-gamma" in extensions`, false},
+		{`"/admin" in path`, true},
+		{`tags contains "users"`, true},
+		{`path contains "/internal"`, false},
+
+		// String operators.
+		{`path startsWith "/admin"`, true},
+		{`path endsWith "/users"`, true},
+		{`operationId matches "^list[A-Z]"`, true},
+		{`path matches "^/public/"`, false},
+
+		// Combined rule.
+		{`method == "GET" && "public" in tags && !(path contains "/admin") && !("x-beta" in extensions)`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Compile(tt.expr, testVars)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			got, err := e.Eval(testValues())
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{``, "unexpected end of expression"},
+		{`method = "GET"`, "unexpected character '='"},
+		{`metod == "GET"`, `unknown variable "metod"`},
+		{`méthod == "GET"`, "unexpected character 'é'"},
+		{`path matches "(["`, "error parsing regexp"},
+		{`path matches operationId`, "takes a string literal"},
+		{`"a" in`, "unexpected end of expression"},
+		{`(true`, `expected ")"`},
+		{`["a" "b"]`, `expected ","`},
+		{`"unterminated`, "unterminated string"},
+		{`true false`, `unexpected "false"`},
+		{`extensions["x-beta"`, `expected "]"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Compile(tt.expr, testVars)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compile() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{`method`, "result is a string, not a boolean"},
+		{`extensions["x-missing"] && true`, "operand of && is null, not a boolean"},
+		{`!path`, "operand of ! is a string, not a boolean"},
+		{`1 in tags || "a" in 1`, "in takes a list, map or string, got a number"},
+		{`1 in extensions`, "map key is a number"},
+		{`path startsWith 1`, "startsWith takes strings"},
+		{`deprecated matches "x"`, "matches takes a string, got a boolean"},
+		{`path["a"] == null`, "can not index a string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := Compile(tt.expr, testVars)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			_, err = e.Eval(testValues())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Eval() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string // Identifier, unquoted string, number or punctuation
+	pos  int    // Byte offset in the source
+}
+
+// punctuation lists punctuation tokens, longest first.
+var punctuation = []string{"==", "!=", "&&", "||", "!", "(", ")", "[", "]", ","}
+
+// lex splits the source into tokens, ending with a [tokenEOF] token.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for pos := 0; pos < len(src); {
+		c := src[pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pos++
+
+		case c == '"' || c == '\'':
+			end := pos + 1
+			for end < len(src) && src[end] != c {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) {
+				return nil, fmt.Errorf("unterminated string at %d", pos)
+			}
+			quoted := src[pos : end+1]
+			if c == '\'' {
+				quoted = `"` + strings.ReplaceAll(strings.ReplaceAll(quoted[1:len(quoted)-1], `\'`, `'`), `"`, `\"`) + `"`
+			}
+			text, err := strconv.Unquote(quoted)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at %d: %w", pos, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: pos})
+			pos = end + 1
+
+		case isDigit(c) || (c == '-' && pos+1 < len(src) && isDigit(src[pos+1])):
+			end := pos + 1
+			for end < len(src) && (isDigit(src[end]) || src[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[pos:end], pos: pos})
+			pos = end
+
+		case isIdentStart(c):
+			end := pos + 1
+			for end < len(src) && (isIdentStart(src[end]) || isDigit(src[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[pos:end], pos: pos})
+			pos = end
+
+		default:
+			found := false
+			for _, p := range punctuation {
+				if strings.HasPrefix(src[pos:], p) {
+					tokens = append(tokens, token{kind: tokenPunct, text: p, pos: pos})
+					pos += len(p)
+					found = true
+					break
+				}
+			}
+			if !found {
+				r, _ := utf8.DecodeRuneInString(src[pos:])
+				return nil, fmt.Errorf("unexpected character %q at %d", r, pos)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+// isIdentStart reports whether c starts an identifier. Identifiers are
+// ASCII, so bytes of multi-byte UTF-8 characters never match.
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
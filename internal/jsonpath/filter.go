@@ -0,0 +1,273 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// filterExpr is the expression of a filter selector.
+type filterExpr interface {
+	// test reports whether the expression is true for the current node.
+	test(root, current any) bool
+}
+
+type orExpr struct {
+	left, right filterExpr
+}
+
+func (e orExpr) test(root, current any) bool {
+	return e.left.test(root, current) || e.right.test(root, current)
+}
+
+type andExpr struct {
+	left, right filterExpr
+}
+
+func (e andExpr) test(root, current any) bool {
+	return e.left.test(root, current) && e.right.test(root, current)
+}
+
+type notExpr struct {
+	x filterExpr
+}
+
+func (e notExpr) test(root, current any) bool {
+	return !e.x.test(root, current)
+}
+
+// existsExpr is true if the query selects at least one node.
+type existsExpr struct {
+	query query
+}
+
+func (e existsExpr) test(root, current any) bool {
+	return len(e.query.nodes(root, current)) > 0
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right operand
+}
+
+func (e comparisonExpr) test(root, current any) bool {
+	left, leftOK := e.left.value(root, current)
+	right, rightOK := e.right.value(root, current)
+	switch e.op {
+	case "==":
+		return leftOK == rightOK && (!leftOK || reflect.DeepEqual(left, right))
+	case "!=":
+		return leftOK != rightOK || (leftOK && !reflect.DeepEqual(left, right))
+	}
+	if !leftOK || !rightOK {
+		return false
+	}
+	switch left := left.(type) {
+	case float64:
+		right, ok := right.(float64)
+		return ok && compare(e.op, left, right)
+	case string:
+		right, ok := right.(string)
+		return ok && compare(e.op, left, right)
+	default:
+		return false
+	}
+}
+
+func compare[T float64 | string](op string, a, b T) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	default:
+		return a >= b
+	}
+}
+
+// operand is a literal or a query of a comparison.
+type operand interface {
+	// value returns the value of the operand, or false if a query does
+	// not select exactly one node.
+	value(root, current any) (any, bool)
+}
+
+type literal struct {
+	v any
+}
+
+func (l literal) value(any, any) (any, bool) {
+	return l.v, true
+}
+
+type query struct {
+	absolute bool
+	segments []segment
+}
+
+func (q query) nodes(root, current any) []Node {
+	start := current
+	if q.absolute {
+		start = root
+	}
+	return selectNodes(q.segments, root, Node{Value: start})
+}
+
+func (q query) value(root, current any) (any, bool) {
+	nodes := q.nodes(root, current)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return normalize(nodes[0].Value), true
+}
+
+// normalize converts numbers to float64, to compare them with literals.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return v
+		}
+		return f
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	default:
+		return v
+	}
+}
+
+func (p *parser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (filterExpr, error) {
+	p.skipSpace()
+	switch {
+	case p.peek() == '!' && !strings.HasPrefix(p.src[p.pos:], "!="):
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{x: x}, nil
+	case p.consume("("):
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+		return x, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return comparisonExpr{op: op, left: left, right: right}, nil
+		}
+	}
+	q, ok := left.(query)
+	if !ok {
+		return nil, p.errorf("expected comparison after literal")
+	}
+	return existsExpr{query: q}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return query{absolute: c == '$', segments: segments}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return literal{v: s}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for !p.eof() && strings.IndexByte("0123456789.eE+-", p.peek()) >= 0 {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.src[start:p.pos])
+		}
+		return literal{v: f}, nil
+	}
+	for _, kw := range []struct {
+		text string
+		v    any
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		rest := p.src[p.pos:]
+		if strings.HasPrefix(rest, kw.text) && (len(rest) == len(kw.text) || !isNameChar(rest[len(kw.text)])) {
+			p.pos += len(kw.text)
+			return literal{v: kw.v}, nil
+		}
+	}
+	return nil, p.errorf("expected query or literal")
+}
+
+// isNameChar reports whether c may continue a keyword, so that e.g.
+// "trueish" is not read as true.
+func isNameChar(c byte) bool {
+	return c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
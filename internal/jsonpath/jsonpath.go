@@ -0,0 +1,359 @@
+// Package jsonpath implements the subset of JSONPath (RFC 9535) used by
+// OpenAPI overlays, evaluated against generic JSON values (map[string]any,
+// []any and scalars):
+//
+//   - the root $, child segments .name, ['name'] and ["name"] with unions
+//     ['a','b'], wildcards .* and [*], and array indices [0] and [-1];
+//   - descendant segments ..name, ..* and ..[...];
+//   - filters [?<expr>] with relative (@) and absolute ($) queries,
+//     comparisons == != < <= > >= to literals or queries, existence
+//     tests, !, && and ||, and parentheses, e.g. [?(@.deprecated == true)].
+//
+// Array slices and function extensions are not supported.
+package jsonpath
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Path is a compiled JSONPath query.
+type Path struct {
+	src      string
+	segments []segment
+}
+
+// Node is a value selected by a query.
+type Node struct {
+	Location []any // Keys (string) and indices (int) leading to the value from the root
+	Value    any
+}
+
+// Compile parses a JSONPath query.
+func Compile(src string) (*Path, error) {
+	p := &parser{src: src}
+	if !p.consume("$") {
+		return nil, p.errorf("query must start with $")
+	}
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	return &Path{src: src, segments: segments}, nil
+}
+
+// String returns the source of the query.
+func (p *Path) String() string {
+	return p.src
+}
+
+// Select returns the nodes of root selected by the query, in document
+// order with object members ordered by key.
+func (p *Path) Select(root any) []Node {
+	return selectNodes(p.segments, root, Node{Value: root})
+}
+
+// segment selects children of a node, or of the node and all its
+// descendants if descendant is set.
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+type selector interface {
+	// selectChildren returns the selected children of n.
+	selectChildren(root any, n Node) []Node
+}
+
+func selectNodes(segments []segment, root any, start Node) []Node {
+	nodes := []Node{start}
+	for _, seg := range segments {
+		var next []Node
+		for _, n := range nodes {
+			inputs := []Node{n}
+			if seg.descendant {
+				inputs = descendants(n)
+			}
+			for _, input := range inputs {
+				for _, sel := range seg.selectors {
+					next = append(next, sel.selectChildren(root, input)...)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// children returns the members of an object or the elements of an array.
+func children(n Node) []Node {
+	switch v := n.Value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		nodes := make([]Node, 0, len(keys))
+		for _, k := range keys {
+			nodes = append(nodes, child(n, k, v[k]))
+		}
+		return nodes
+	case []any:
+		nodes := make([]Node, 0, len(v))
+		for i, item := range v {
+			nodes = append(nodes, child(n, i, item))
+		}
+		return nodes
+	default:
+		return nil
+	}
+}
+
+// descendants returns the node and all its descendants.
+func descendants(n Node) []Node {
+	nodes := []Node{n}
+	for _, c := range children(n) {
+		nodes = append(nodes, descendants(c)...)
+	}
+	return nodes
+}
+
+func child(n Node, key, value any) Node {
+	location := make([]any, len(n.Location), len(n.Location)+1)
+	copy(location, n.Location)
+	return Node{Location: append(location, key), Value: value}
+}
+
+type nameSelector struct {
+	name string
+}
+
+func (s nameSelector) selectChildren(_ any, n Node) []Node {
+	if m, ok := n.Value.(map[string]any); ok {
+		if v, ok := m[s.name]; ok {
+			return []Node{child(n, s.name, v)}
+		}
+	}
+	return nil
+}
+
+type wildcardSelector struct{}
+
+func (wildcardSelector) selectChildren(_ any, n Node) []Node {
+	return children(n)
+}
+
+type indexSelector struct {
+	index int
+}
+
+func (s indexSelector) selectChildren(_ any, n Node) []Node {
+	list, ok := n.Value.([]any)
+	if !ok {
+		return nil
+	}
+	i := s.index
+	if i < 0 {
+		i += len(list)
+	}
+	if i < 0 || i >= len(list) {
+		return nil
+	}
+	return []Node{child(n, i, list[i])}
+}
+
+type filterSelector struct {
+	expr filterExpr
+}
+
+func (s filterSelector) selectChildren(root any, n Node) []Node {
+	var nodes []Node
+	for _, c := range children(n) {
+		if s.expr.test(root, c.Value) {
+			nodes = append(nodes, c)
+		}
+	}
+	return nodes
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) consume(s string) bool {
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && strings.IndexByte(" \t\n\r", p.peek()) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%s at %d", fmt.Sprintf(format, args...), p.pos)
+}
+
+// parseSegments parses segments until there are no more.
+func (p *parser) parseSegments() ([]segment, error) {
+	var segments []segment
+	for {
+		var seg segment
+		switch {
+		case p.consume(".."):
+			seg.descendant = true
+			if p.peek() == '[' {
+				p.pos++
+				selectors, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				seg.selectors = selectors
+				break
+			}
+			sel, err := p.parseDotSelector()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = []selector{sel}
+		case p.consume("."):
+			sel, err := p.parseDotSelector()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = []selector{sel}
+		case p.consume("["):
+			selectors, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = selectors
+		default:
+			return segments, nil
+		}
+		segments = append(segments, seg)
+	}
+}
+
+// parseDotSelector parses the wildcard or member name after a dot.
+func (p *parser) parseDotSelector() (selector, error) {
+	if p.consume("*") {
+		return wildcardSelector{}, nil
+	}
+	start := p.pos
+	for !p.eof() && strings.IndexByte(".[] \t\n\r()=!<>&|,", p.peek()) < 0 {
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected member name")
+	}
+	return nameSelector{name: p.src[start:p.pos]}, nil
+}
+
+// parseBracket parses selectors after the opening bracket.
+func (p *parser) parseBracket() ([]selector, error) {
+	var selectors []selector
+	for {
+		p.skipSpace()
+		var sel selector
+		switch c := p.peek(); {
+		case c == '*':
+			p.pos++
+			sel = wildcardSelector{}
+		case c == '\'' || c == '"':
+			name, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			sel = nameSelector{name: name}
+		case c == '?':
+			p.pos++
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			sel = filterSelector{expr: expr}
+		case c == '-' || (c >= '0' && c <= '9'):
+			start := p.pos
+			p.pos++
+			for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+				p.pos++
+			}
+			i, err := strconv.Atoi(p.src[start:p.pos])
+			if err != nil {
+				return nil, p.errorf("invalid index %q", p.src[start:p.pos])
+			}
+			if p.peek() == ':' {
+				return nil, p.errorf("array slices are not supported")
+			}
+			sel = indexSelector{index: i}
+		default:
+			return nil, p.errorf("expected selector")
+		}
+		selectors = append(selectors, sel)
+
+		p.skipSpace()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+
+// parseString parses a single or double quoted string.
+func (p *parser) parseString() (string, error) {
+	quote := p.peek()
+	start := p.pos
+	p.pos++
+	var s strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		p.pos++
+		switch c {
+		case quote:
+			return s.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+			escaped := p.peek()
+			p.pos++
+			switch escaped {
+			case 'n':
+				s.WriteByte('\n')
+			case 't':
+				s.WriteByte('\t')
+			default:
+				s.WriteByte(escaped)
+			}
+		default:
+			s.WriteByte(c)
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
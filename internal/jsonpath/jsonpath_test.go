@@ -0,0 +1,122 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testDoc = `{
+	"info": {"title": "Pets", "version": "1"},
+	"tags": ["a", "b", "c"],
+	"paths": {
+		"/pets": {
+			"get": {"operationId": "listPets", "deprecated": true, "x-rank": 10},
+			"post": {"operationId": "addPet", "x-rank": 2}
+		},
+		"/users": {
+			"get": {"operationId": "listUsers", "x-rank": 9007199254740993}
+		}
+	}
+}`
+
+// decodeTestDoc decodes the test document with numbers as json.Number, as
+// specs are decoded for overlays.
+func decodeTestDoc(t *testing.T) any {
+	t.Helper()
+	decoder := json.NewDecoder(strings.NewReader(testDoc))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	return doc
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string // Locations of selected nodes
+	}{
+		{`$`, []string{"[]"}},
+		{`$.info.title`, []string{"[info title]"}},
+		{`$['info']["version"]`, []string{"[info version]"}},
+		{`$.paths['/pets'].get`, []string{"[paths /pets get]"}},
+		{`$.missing.title`, nil},
+
+		// Unions, wildcards and indices.
+		{`$.info['title','version']`, []string{"[info title]", "[info version]"}},
+		{`$.info.*`, []string{"[info title]", "[info version]"}},
+		{`$.tags[*]`, []string{"[tags 0]", "[tags 1]", "[tags 2]"}},
+		{`$.tags[0, 2]`, []string{"[tags 0]", "[tags 2]"}},
+		{`$.tags[-1]`, []string{"[tags 2]"}},
+		{`$.tags[3]`, nil},
+		{`$.tags[-4]`, nil},
+
+		// Descendants.
+		{`$..operationId`, []string{
+			"[paths /pets get operationId]",
+			"[paths /pets post operationId]",
+			"[paths /users get operationId]",
+		}},
+		{`$.paths..[?@.deprecated]`, []string{"[paths /pets get]"}},
+
+		// Filters, with numbers of the document decoded as json.Number.
+		{`$.paths.*[?(@.deprecated == true)]`, []string{"[paths /pets get]"}},
+		{`$.paths.*[?@.deprecated != true]`, []string{"[paths /pets post]", "[paths /users get]"}},
+		{`$.paths.*[?@['x-rank'] == 2]`, []string{"[paths /pets post]"}},
+		{`$.paths.*[?@.x-rank > 5 && @.x-rank < 100]`, []string{"[paths /pets get]"}},
+		{`$.paths.*[?@.x-rank >= 1e3]`, []string{"[paths /users get]"}},
+		{`$.paths.*[?@.operationId == 'addPet' || @.operationId == "listUsers"]`, []string{
+			"[paths /pets post]", "[paths /users get]",
+		}},
+		{`$.paths.*[?!(@.deprecated)]`, []string{"[paths /pets post]", "[paths /users get]"}},
+		{`$.paths[?@.post]`, []string{"[paths /pets]"}},
+		{`$.paths.*[?@.operationId == $.paths['/pets'].post.operationId]`, []string{"[paths /pets post]"}},
+		{`$.tags[?@ > 'a']`, []string{"[tags 1]", "[tags 2]"}},
+	}
+	doc := decodeTestDoc(t)
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			p, err := Compile(tt.path)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			var got []string
+			for _, n := range p.Select(doc) {
+				got = append(got, fmt.Sprint(n.Location))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr string
+	}{
+		{`info.title`, "query must start with $"},
+		{`$.`, "expected member name"},
+		{`$.tags[1:2]`, "array slices are not supported"},
+		{`$['info`, "unterminated string"},
+		{`$[?@.a == 'b]`, "unterminated string"},
+		{`$.info[`, "expected selector"},
+		{`$.info['title' 'version']`, "expected , or ]"},
+		{`$[?(@.a == 1]`, "expected )"},
+		{`$[?1]`, "expected comparison after literal"},
+		{`$[?@.a == trueish]`, "expected query or literal"},
+		{`$.info]`, "unexpected \"]\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, err := Compile(tt.path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compile() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
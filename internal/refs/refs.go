@@ -1,6 +1,10 @@
 package refs
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/zguydev/openapi-filter/internal/components"
+)
 
 func ParseRef(ref string) (def, name string, ok bool) {
 	elems := strings.Split(ref, "/")
@@ -10,3 +14,21 @@ func ParseRef(ref string) (def, name string, ok bool) {
 	def, name = elems[2], elems[3]
 	return def, name, true
 }
+
+// ComponentRef returns the local ref of the component, e.g. "#/components/schemas/Pet".
+func ComponentRef(typ components.ComponentType, name string) string {
+	return "#/components/" + components.ComponentTypeToDef(typ) + "/" + name
+}
+
+// NormalizeRef converts a component ref given in a short form, e.g.
+// "schemas/Pet" or "components/schemas/Pet", to the local ref form
+// "#/components/schemas/Pet".
+func NormalizeRef(ref string) string {
+	if strings.HasPrefix(ref, "#/") {
+		return ref
+	}
+	ref = strings.TrimPrefix(ref, "#")
+	ref = strings.TrimPrefix(ref, "/")
+	ref = strings.TrimPrefix(ref, "components/")
+	return "#/components/" + ref
+}
@@ -2,32 +2,126 @@ package refs
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/getkin/kin-openapi/openapi3"
 
 	"github.com/zguydev/openapi-filter/internal/components"
 )
 
+// RefsCollector collects refs used by operations and components. Every
+// ref is attributed to the origin it was collected from, e.g. a selected
+// operation or component, see [RefsCollector.SetOrigin]. The collector
+// also records the parent edge of every ref: the ref or origin it was
+// referenced from.
 type RefsCollector struct {
-	refs map[string]struct{}
+	refs    map[string]struct{}
+	origins map[string]map[string]struct{}
+	parents map[string]map[string]struct{}
+	roots   map[string]struct{}
+	origin  string
+	parent  string
 }
 
 func NewRefsCollector() *RefsCollector {
 	return &RefsCollector{
-		refs: make(map[string]struct{}),
+		refs:    make(map[string]struct{}),
+		origins: make(map[string]map[string]struct{}),
+		parents: make(map[string]map[string]struct{}),
+		roots:   make(map[string]struct{}),
 	}
 }
 
-func (rc *RefsCollector) AddRef(ref string) {
+// SetOrigin sets the origin of refs collected afterwards.
+func (rc *RefsCollector) SetOrigin(origin string) {
+	rc.origin, rc.parent = origin, origin
+	rc.roots[origin] = struct{}{}
+}
+
+// enter makes ref the parent of refs collected until the returned
+// function is called.
+func (rc *RefsCollector) enter(ref string) (leave func()) {
+	parent := rc.parent
+	rc.parent = ref
+	return func() { rc.parent = parent }
+}
+
+// AddRef adds a ref collected from the current origin. Returns false if
+// the ref was already collected from this origin, so it is not traversed
+// again, which also stops traversal of circular refs.
+func (rc *RefsCollector) AddRef(ref string) (added bool) {
 	rc.refs[ref] = struct{}{}
+	addToSet(rc.parents, ref, rc.parent)
+	origins, ok := rc.origins[ref]
+	if !ok {
+		origins = make(map[string]struct{})
+		rc.origins[ref] = origins
+	}
+	if _, ok := origins[rc.origin]; ok {
+		return false
+	}
+	origins[rc.origin] = struct{}{}
+	return true
 }
 
 func (rc *RefsCollector) Refs() map[string]struct{} {
 	return rc.refs
 }
 
+func addToSet(m map[string]map[string]struct{}, key, value string) {
+	set, ok := m[key]
+	if !ok {
+		set = make(map[string]struct{})
+		m[key] = set
+	}
+	set[value] = struct{}{}
+}
+
+// Chain returns the shortest chain of refs from an origin to ref, starting
+// with the origin and ending with ref. Returns nil if ref was not collected.
+func (rc *RefsCollector) Chain(ref string) []string {
+	if _, ok := rc.refs[ref]; !ok {
+		return nil
+	}
+	next := map[string]string{ref: ""}
+	queue := []string{ref}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if _, ok := rc.roots[node]; ok && node != ref {
+			chain := []string{node}
+			for n := next[node]; n != ""; n = next[n] {
+				chain = append(chain, n)
+			}
+			return chain
+		}
+		parents := make([]string, 0, len(rc.parents[node]))
+		for parent := range rc.parents[node] {
+			parents = append(parents, parent)
+		}
+		sort.Strings(parents) // deterministic choice between chains of equal length
+		for _, parent := range parents {
+			if _, seen := next[parent]; !seen {
+				next[parent] = node
+				queue = append(queue, parent)
+			}
+		}
+	}
+	return nil
+}
+
+// Origins returns sorted origins the ref was collected from.
+func (rc *RefsCollector) Origins(ref string) []string {
+	origins := make([]string, 0, len(rc.origins[ref]))
+	for origin := range rc.origins[ref] {
+		origins = append(origins, origin)
+	}
+	sort.Strings(origins)
+	return origins
+}
+
 func (rc *RefsCollector) CollectOperation(op *openapi3.Operation) {
-	rc.collectParameters(op.Parameters)
+	rc.CollectParameters(op.Parameters)
 	if op.RequestBody != nil {
 		rc.collectRequestBodyRef(op.RequestBody)
 	}
@@ -35,14 +129,9 @@ func (rc *RefsCollector) CollectOperation(op *openapi3.Operation) {
 	rc.collectCallbacks(op.Callbacks)
 }
 
-func (rc *RefsCollector) collectParameters(params openapi3.Parameters) {
+func (rc *RefsCollector) CollectParameters(params openapi3.Parameters) {
 	for _, param := range params {
-		if param.Ref != "" {
-			rc.AddRef(param.Ref)
-		}
-		if p := param.Value; p != nil {
-			rc.collectParameter(p)
-		}
+		rc.collectParameterRef(param)
 	}
 }
 
@@ -54,7 +143,10 @@ func (rc *RefsCollector) collectParameter(param *openapi3.Parameter) {
 
 func (rc *RefsCollector) collectParameterRef(paramr *openapi3.ParameterRef) {
 	if paramr.Ref != "" {
-		rc.AddRef(paramr.Ref)
+		if !rc.AddRef(paramr.Ref) {
+			return
+		}
+		defer rc.enter(paramr.Ref)()
 	}
 	if p := paramr.Value; p != nil {
 		rc.collectParameter(p)
@@ -66,7 +158,10 @@ func (rc *RefsCollector) collectSchemaRef(scr *openapi3.SchemaRef) {
 		return
 	}
 	if scr.Ref != "" {
-		rc.AddRef(scr.Ref)
+		if !rc.AddRef(scr.Ref) {
+			return
+		}
+		defer rc.enter(scr.Ref)()
 	}
 	if scr.Value != nil {
 		rc.collectSchema(scr.Value)
@@ -97,7 +192,10 @@ func (rc *RefsCollector) collectEncoding(enc *openapi3.Encoding) {
 
 func (rc *RefsCollector) collectHeaderRef(hr *openapi3.HeaderRef) {
 	if hr.Ref != "" {
-		rc.AddRef(hr.Ref)
+		if !rc.AddRef(hr.Ref) {
+			return
+		}
+		defer rc.enter(hr.Ref)()
 	}
 	if h := hr.Value; h != nil {
 		rc.collectParameter(&h.Parameter) // Header type embeds the Parameter type
@@ -112,7 +210,10 @@ func (rc *RefsCollector) collectHeaders(headers openapi3.Headers) {
 
 func (rc *RefsCollector) collectRequestBodyRef(rbr *openapi3.RequestBodyRef) {
 	if rbr.Ref != "" {
-		rc.AddRef(rbr.Ref)
+		if !rc.AddRef(rbr.Ref) {
+			return
+		}
+		defer rc.enter(rbr.Ref)()
 	}
 	if rb := rbr.Value; rb != nil {
 		rc.collectRequestBodyRefs(rb)
@@ -125,7 +226,10 @@ func (rc *RefsCollector) collectRequestBodyRefs(rb *openapi3.RequestBody) {
 
 func (rc *RefsCollector) collectResponseRef(respr *openapi3.ResponseRef) {
 	if respr.Ref != "" {
-		rc.AddRef(respr.Ref)
+		if !rc.AddRef(respr.Ref) {
+			return
+		}
+		defer rc.enter(respr.Ref)()
 	}
 	if r := respr.Value; r != nil {
 		rc.collectHeaders(r.Headers)
@@ -155,11 +259,14 @@ func (rc *RefsCollector) collectLinkRef(lr *openapi3.LinkRef) {
 
 func (rc *RefsCollector) collectCallbackRef(cbr *openapi3.CallbackRef) {
 	if cbr.Ref != "" {
-		rc.AddRef(cbr.Ref)
+		if !rc.AddRef(cbr.Ref) {
+			return
+		}
+		defer rc.enter(cbr.Ref)()
 	}
 	if c := cbr.Value; c != nil {
 		for _, path := range c.Map() {
-			rc.collectPathItem(path)
+			rc.CollectPathItem(path)
 		}
 	}
 }
@@ -170,14 +277,17 @@ func (rc *RefsCollector) collectCallbacks(callbacks openapi3.Callbacks) {
 	}
 }
 
-func (rc *RefsCollector) collectPathItem(path *openapi3.PathItem) {
+func (rc *RefsCollector) CollectPathItem(path *openapi3.PathItem) {
 	if path.Ref != "" {
-		rc.AddRef(path.Ref)
+		if !rc.AddRef(path.Ref) {
+			return
+		}
+		defer rc.enter(path.Ref)()
 	}
 	for _, op := range path.Operations() {
 		rc.CollectOperation(op)
 	}
-	rc.collectParameters(path.Parameters)
+	rc.CollectParameters(path.Parameters)
 }
 
 func (rc *RefsCollector) collectSchemaRefs(scrs openapi3.SchemaRefs) {
@@ -244,3 +354,13 @@ func (rc *RefsCollector) CollectComponent(
 		panic(fmt.Errorf("unsupported component type: %v", typ))
 	}
 }
+
+// CollectSecurityRequirements collects refs of security schemes used by
+// the security requirements.
+func (rc *RefsCollector) CollectSecurityRequirements(reqs openapi3.SecurityRequirements) {
+	for _, req := range reqs {
+		for name := range req {
+			rc.AddRef(ComponentRef(components.ContentTypeSecuritySchema, name))
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package refs
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const spec = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+  /owners:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Owner"}
+  /orders:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Order"}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        owner: {$ref: "#/components/schemas/Owner"}
+        parent: {$ref: "#/components/schemas/Pet"}
+    Owner:
+      type: object
+      properties:
+        pets: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+        address: {$ref: "#/components/schemas/Address"}
+    Order:
+      type: object
+      properties:
+        pet: {$ref: "#/components/schemas/Pet"}
+        address: {$ref: "#/components/schemas/Address"}
+    Address: {type: object}
+    Unused: {type: object}
+`
+
+func collect(t *testing.T) *RefsCollector {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatalf("LoadFromData() error = %v", err)
+	}
+	rc := NewRefsCollector()
+	for _, path := range []string{"/pets", "/owners", "/orders"} {
+		rc.SetOrigin("GET " + path)
+		rc.CollectOperation(doc.Paths.Value(path).Get)
+	}
+	return rc
+}
+
+func TestRefsCollectorChain(t *testing.T) {
+	rc := collect(t)
+	tests := []struct {
+		ref  string
+		want []string
+	}{
+		// Referenced by an operation and by schemas, including itself.
+		{ref: "#/components/schemas/Pet", want: []string{"GET /pets", "#/components/schemas/Pet"}},
+		// Referenced by Pet and referencing Pet back.
+		{ref: "#/components/schemas/Owner", want: []string{"GET /owners", "#/components/schemas/Owner"}},
+		// Several shortest chains: the first parent by name is chosen.
+		{ref: "#/components/schemas/Address", want: []string{"GET /orders", "#/components/schemas/Order", "#/components/schemas/Address"}},
+		{ref: "#/components/schemas/Unused"},
+		{ref: "GET /pets"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			if got := rc.Chain(tt.ref); !slices.Equal(got, tt.want) {
+				t.Errorf("Chain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefsCollectorChainThroughCycle(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatalf("LoadFromData() error = %v", err)
+	}
+	rc := NewRefsCollector()
+	rc.SetOrigin("GET /pets")
+	rc.CollectOperation(doc.Paths.Value("/pets").Get)
+
+	want := []string{"GET /pets", "#/components/schemas/Pet", "#/components/schemas/Owner", "#/components/schemas/Address"}
+	if got := rc.Chain("#/components/schemas/Address"); !slices.Equal(got, want) {
+		t.Errorf("Chain() = %v, want %v", got, want)
+	}
+	if _, ok := rc.Refs()["#/components/schemas/Order"]; ok {
+		t.Error("Refs() has Order, want only refs reachable from GET /pets")
+	}
+}
+
+func TestRefsCollectorOrigins(t *testing.T) {
+	rc := collect(t)
+	tests := []struct {
+		ref  string
+		want []string
+	}{
+		{ref: "#/components/schemas/Pet", want: []string{"GET /orders", "GET /owners", "GET /pets"}},
+		{ref: "#/components/schemas/Owner", want: []string{"GET /orders", "GET /owners", "GET /pets"}},
+		{ref: "#/components/schemas/Order", want: []string{"GET /orders"}},
+		{ref: "#/components/schemas/Address", want: []string{"GET /orders", "GET /owners", "GET /pets"}},
+		{ref: "#/components/schemas/Unused", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			if got := rc.Origins(tt.ref); !slices.Equal(got, tt.want) {
+				t.Errorf("Origins() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
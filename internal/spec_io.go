@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 
@@ -16,24 +17,66 @@ func LoadSpecFromFile(loader *openapi3.Loader, specPath string) (*openapi3.T, er
 	return doc, nil
 }
 
-func WriteSpecToFile(doc *openapi3.T, specPath string) error {
+// EncodeSpec encodes the spec to YAML as it is written by [WriteSpecToFile].
+func EncodeSpec(doc *openapi3.T) ([]byte, error) {
 	yamlData, err := doc.MarshalYAML()
 	if err != nil {
-		return fmt.Errorf("doc.MarshalYAML: %w", err)
+		return nil, fmt.Errorf("doc.MarshalYAML: %w", err)
 	}
+	return encodeYAML(yamlData)
+}
 
-	outputFile, err := os.Create(specPath)
-	if err != nil {
-		return fmt.Errorf("os.Create: %w", err)
+func encodeYAML(yamlData any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(yamlData); err != nil {
+		return nil, fmt.Errorf("encoder.Encode: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("encoder.Close: %w", err)
 	}
-	defer outputFile.Close() //nolint:errcheck
+	return buf.Bytes(), nil
+}
 
-	encoder := yaml.NewEncoder(outputFile)
-	encoder.SetIndent(2)
-	defer encoder.Close() //nolint:errcheck
+func WriteSpecToFile(doc *openapi3.T, specPath string) error {
+	data, err := EncodeSpec(doc)
+	if err != nil {
+		return fmt.Errorf("EncodeSpec: %w", err)
+	}
+	if err := os.WriteFile(specPath, data, 0o644); err != nil { //nolint:gosec
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	return nil
+}
 
-	if err := encoder.Encode(yamlData); err != nil {
-		return fmt.Errorf("encoder.Encode: %w", err)
+// WriteYAMLToFile writes v encoded to YAML, e.g. an index of specs.
+func WriteYAMLToFile(v any, path string) error {
+	data, err := encodeYAML(v)
+	if err != nil {
+		return fmt.Errorf("encodeYAML: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil { //nolint:gosec
+		return fmt.Errorf("os.WriteFile: %w", err)
 	}
 	return nil
 }
+
+// DecodeSpecFile decodes a YAML or JSON spec file into generic values,
+// which can be compared semantically regardless of formatting and key order.
+func DecodeSpecFile(specPath string) (any, error) {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+	return DecodeSpec(data)
+}
+
+// DecodeSpec decodes YAML or JSON spec data into generic values.
+func DecodeSpec(data []byte) (any, error) {
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal: %w", err)
+	}
+	return v, nil
+}
@@ -27,7 +27,8 @@ func NewFallbackLogger() *zap.Logger {
 	return logger
 }
 
-func NewLogger(cfg *config.LoggerConfig) (*zap.Logger, error) {
+// NewLogger creates a logger writing to outputPaths, stdout by default.
+func NewLogger(cfg *config.LoggerConfig, outputPaths ...string) (*zap.Logger, error) {
 	level, err := zap.ParseAtomicLevel(cfg.Level)
 	if err != nil {
 		return nil, fmt.Errorf("wrong logger level in config: %w", err)
@@ -42,6 +43,9 @@ func NewLogger(cfg *config.LoggerConfig) (*zap.Logger, error) {
 	}
 	zapCfg.Level = level
 	zapCfg.OutputPaths = []string{"stdout"}
+	if len(outputPaths) > 0 {
+		zapCfg.OutputPaths = outputPaths
+	}
 
 	logger, err := zapCfg.Build()
 	if err != nil {
@@ -0,0 +1,123 @@
+// Package walk provides traversal of the OpenAPI document model.
+package walk
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Visitor is called for every struct of the document model with the JSON
+// pointer of the struct in the document. The struct value is addressable,
+// so the visitor can modify it.
+type Visitor func(pointer string, v reflect.Value)
+
+// Walk calls visit for every struct of the document model reachable from
+// root, e.g. *openapi3.T. Ref wrappers (e.g. *openapi3.SchemaRef) are
+// visited, but their values are not visited if Ref is set, since the
+// referenced component is visited at its definition. This also prevents
+// infinite traversal of circular refs.
+func Walk(root any, visit Visitor) {
+	walkValue(reflect.ValueOf(root), "", visit)
+}
+
+func walkValue(v reflect.Value, pointer string, visit Visitor) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		walkValue(v.Elem(), pointer, visit)
+	case reflect.Struct:
+		walkStruct(v, pointer, visit)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			walkElem(iter.Value(), pointer+"/"+EscapePointer(iter.Key().String()), visit)
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			walkElem(v.Index(i), pointer+"/"+strconv.Itoa(i), visit)
+		}
+	}
+}
+
+// walkElem walks a map or slice element, which is only walked if it is
+// a pointer to a struct, as it is not addressable otherwise.
+func walkElem(v reflect.Value, pointer string, visit Visitor) {
+	if v.Kind() == reflect.Pointer || v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+		walkValue(v, pointer, visit)
+	}
+}
+
+func walkStruct(v reflect.Value, pointer string, visit Visitor) {
+	if !v.CanAddr() {
+		return
+	}
+	visit(pointer, v)
+
+	if ref, value, ok := refWrapper(v); ok {
+		if ref == "" {
+			walkValue(value, pointer, visit)
+		}
+		return
+	}
+
+	// Paths, Responses and Callback keep their items in unexported maps.
+	if m := v.Addr().MethodByName("Map"); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 {
+		walkValue(m.Call(nil)[0], pointer, visit)
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous {
+			walkValue(v.Field(i), pointer, visit)
+			continue
+		}
+		name := JSONName(field)
+		if name == "" {
+			continue
+		}
+		walkValue(v.Field(i), pointer+"/"+EscapePointer(name), visit)
+	}
+}
+
+// refWrapper reports whether v is a ref wrapper struct, e.g.
+// openapi3.SchemaRef, and returns its ref and value.
+func refWrapper(v reflect.Value) (ref string, value reflect.Value, ok bool) {
+	refField := v.FieldByName("Ref")
+	valueField := v.FieldByName("Value")
+	if !refField.IsValid() || refField.Kind() != reflect.String ||
+		!valueField.IsValid() || valueField.Kind() != reflect.Pointer {
+		return "", reflect.Value{}, false
+	}
+	return refField.String(), valueField, true
+}
+
+// IsRefWrapper reports whether v is a ref wrapper struct, e.g. openapi3.SchemaRef.
+func IsRefWrapper(v reflect.Value) bool {
+	_, _, ok := refWrapper(v)
+	return ok
+}
+
+// JSONName returns the name of the field in the JSON document, or an
+// empty string if the field is not marshaled as is (e.g. extensions).
+func JSONName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" || strings.HasPrefix(name, "__") {
+		return ""
+	}
+	return name
+}
+
+// EscapePointer escapes a JSON pointer reference token.
+func EscapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
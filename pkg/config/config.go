@@ -8,17 +8,131 @@ package config
 type Config struct {
 	Tool         ToolConfig `koanf:"x-openapi-filter"`
 	FilterConfig `koanf:",squash"`
+	Inputs       []InputConfig `koanf:"inputs"` // Specs to filter and merge, used by the merge command
+	Merge        MergeConfig   `koanf:"merge"`  // Merging configuration
+}
+
+// InputConfig defines a spec to merge along with its own filter configuration.
+type InputConfig struct {
+	Spec         string `koanf:"spec"` // Path to the spec, relative to the config file
+	Name         string `koanf:"name"` // Name used to rename conflicting elements (default: spec file name)
+	FilterConfig `koanf:",squash"`
+}
+
+// MergeConfig specifies how filtered input specs are merged.
+type MergeConfig struct {
+	Conflicts string `koanf:"conflicts"` // Conflict policy: "error" (default), "prefer-first" or "rename"
 }
 
 // FilterConfig defines the configuration for filtering an OpenAPI spec.
 // It specifies which parts of the spec should be included in the output.
 type FilterConfig struct {
-	Servers      bool                    `koanf:"servers"`      // Include servers section
-	Paths        map[string][]string     `koanf:"paths"`        // Map of paths to allowed HTTP methods
-	Components   *FilterComponentsConfig `koanf:"components"`   // Component filtering configuration
-	Security     bool                    `koanf:"security"`     // Include security requirements
-	Tags         bool                    `koanf:"tags"`         // Include tags
-	ExternalDocs bool                    `koanf:"externalDocs"` // Include external documentation
+	PassThrough     bool                    `koanf:"passThrough"`     // Keep the whole spec as is, ignoring paths and top-level filters
+	PruneComponents bool                    `koanf:"pruneComponents"` // In pass-through mode, drop components not reachable from any operation
+	Servers         bool                    `koanf:"servers"`         // Include servers
+	ServerRules     ServersConfig           `koanf:"serverRules"`     // Selection and overrides of servers
+	Paths           map[string][]string     `koanf:"paths"`           // Map of paths to allowed HTTP methods
+	Select          *SelectConfig           `koanf:"select"`          // Operations to keep in addition to paths
+	Components      *FilterComponentsConfig `koanf:"components"`      // Component filtering configuration
+	Security        bool                    `koanf:"security"`        // Include security requirements
+	Tags            bool                    `koanf:"tags"`            // Include tags
+	ExternalDocs    bool                    `koanf:"externalDocs"`    // Include external documentation
+	Strip           *StripConfig            `koanf:"strip"`           // Fields to remove from the filtered spec
+	Properties      *FilterPropertiesConfig `koanf:"properties"`      // Schema properties filtering configuration
+	StatusCodes     *IncludeExcludeConfig   `koanf:"statusCodes"`     // Response status codes to keep (e.g. "200", "4XX", "default")
+	ContentTypes    *IncludeExcludeConfig   `koanf:"contentTypes"`    // Request and response content types to keep (e.g. "application/json")
+	Parameters      *FilterParametersConfig `koanf:"parameters"`      // Parameters filtering configuration
+	Info            *InfoConfig             `koanf:"info"`            // Overrides of the info section
+	Rewrite         *RewriteConfig          `koanf:"rewrite"`         // Rewriting of path keys
+	Rename          *RenameConfig           `koanf:"rename"`          // Renaming of components and operationIds
+	Pipeline        *PipelineConfig         `koanf:"pipeline"`        // Order of filtering stages
+	Overlays        []string                `koanf:"overlays"`        // OpenAPI Overlay files applied to the filtered spec in order, relative to the config file
+}
+
+// PipelineConfig specifies the order of filtering stages. Stages before
+// the "select" stage transform the source spec, stages after it transform
+// the filtered spec.
+type PipelineConfig struct {
+	Stages  []string `koanf:"stages"`  // Stages to run in order (default: properties, responses, parameters, select, rewrite, rename, info, strip, overlay)
+	Disable []string `koanf:"disable"` // Stages not to run
+}
+
+// RenameConfig specifies renaming of components and operationIds in the
+// filtered OpenAPI spec. Refs to renamed components are rewritten.
+// Components not renamed explicitly are named by the template of their
+// type, or prefixed with Prefix if there is no template.
+type RenameConfig struct {
+	Components   map[string]map[string]string `koanf:"components"`   // Component definition (e.g. "schemas") -> name -> new name
+	Prefix       string                       `koanf:"prefix"`       // Prefix of all component names (e.g. "Billing")
+	Templates    map[string]string            `koanf:"templates"`    // Component definition -> name template (e.g. "{{ .Name }}V2")
+	OperationIDs map[string]string            `koanf:"operationIds"` // Operation ID -> new operation ID
+}
+
+// SelectConfig specifies operations to keep by their properties, in
+// addition to operations kept by paths.
+type SelectConfig struct {
+	Tags         []string `koanf:"tags"`         // Keep operations with any of these tags
+	OperationIDs []string `koanf:"operationIds"` // Keep operations with these operation IDs
+	Expression   string   `koanf:"expression"`   // Keep operations matching the expression, e.g. `method == "GET" && "public" in tags`
+}
+
+// RewriteConfig specifies rewriting of the filtered OpenAPI spec.
+type RewriteConfig struct {
+	Paths []PathRewriteConfig `koanf:"paths"` // Path rewrite rules, the first matching rule applies
+}
+
+// PathRewriteConfig defines a rule rewriting path keys either by prefix or
+// by regular expression. Exactly one of Prefix and Regex must be set.
+type PathRewriteConfig struct {
+	Prefix  string `koanf:"prefix"`  // Path prefix to replace, matched at segment boundaries
+	Regex   string `koanf:"regex"`   // Regular expression to replace
+	Replace string `koanf:"replace"` // Replacement, may reference regex groups (e.g. "$1")
+}
+
+// InfoConfig specifies overrides of the info section of the filtered
+// OpenAPI spec. Values are Go templates executed with the source info as
+// data (e.g. "{{ .Title }} (Partner)"). Empty values keep source values.
+type InfoConfig struct {
+	Title          string         `koanf:"title"`          // API title
+	Version        string         `koanf:"version"`        // API version
+	Description    string         `koanf:"description"`    // API description
+	TermsOfService string         `koanf:"termsOfService"` // Terms of service URL
+	Contact        *ContactConfig `koanf:"contact"`        // Contact information
+	License        *LicenseConfig `koanf:"license"`        // License information
+}
+
+// ContactConfig defines contact information of the API.
+type ContactConfig struct {
+	Name  string `koanf:"name"`  // Contact name
+	URL   string `koanf:"url"`   // Contact URL
+	Email string `koanf:"email"` // Contact email
+}
+
+// LicenseConfig defines license information of the API.
+type LicenseConfig struct {
+	Name string `koanf:"name"` // License name
+	URL  string `koanf:"url"`  // License URL
+}
+
+// FilterParametersConfig specifies parameters to drop from operations,
+// path items and components of the filtered OpenAPI spec.
+type FilterParametersConfig struct {
+	Drop []ParameterRuleConfig `koanf:"drop"` // Rules of parameters to drop
+}
+
+// ParameterRuleConfig matches parameters. A rule matches a parameter if
+// all of its set fields match.
+type ParameterRuleConfig struct {
+	In   string `koanf:"in"`   // Parameter location: "query", "header", "path" or "cookie"
+	Name string `koanf:"name"` // Glob pattern of parameter name (e.g. "X-Tenant*")
+}
+
+// IncludeExcludeConfig specifies patterns of values to keep. If Include is
+// not empty, only matching values are kept. Values matching Exclude are
+// dropped.
+type IncludeExcludeConfig struct {
+	Include []string `koanf:"include"` // Patterns of values to keep
+	Exclude []string `koanf:"exclude"` // Patterns of values to drop
 }
 
 // FilterComponentsConfig specifies which components should be included in the
@@ -35,10 +149,54 @@ type FilterComponentsConfig struct {
 	Callbacks       []string `koanf:"callbacks"`       // List of callback names to include
 }
 
+// ServersConfig specifies which servers are kept in the filtered OpenAPI
+// spec and how they are overridden. The rules apply to top-level servers
+// kept by FilterConfig.Servers or pass-through mode, and to path and
+// operation servers.
+type ServersConfig struct {
+	URLs         []string          `koanf:"urls"`         // Glob patterns of server URLs to keep
+	Environments []string          `koanf:"environments"` // Keep servers with x-environment extension set to one of these values
+	Replace      []ServerConfig    `koanf:"replace"`      // Servers replacing top-level servers; path and operation servers are removed
+	Variables    map[string]string `koanf:"variables"`    // Overrides of server variable default values
+}
+
+// ServerConfig defines a server of the filtered OpenAPI spec.
+type ServerConfig struct {
+	URL         string                          `koanf:"url"`         // Server URL, may contain variables (e.g. "https://{region}.example.com")
+	Description string                          `koanf:"description"` // Server description
+	Variables   map[string]ServerVariableConfig `koanf:"variables"`   // Server variables
+}
+
+// ServerVariableConfig defines a server variable.
+type ServerVariableConfig struct {
+	Default     string   `koanf:"default"`     // Default value
+	Enum        []string `koanf:"enum"`        // Allowed values
+	Description string   `koanf:"description"` // Variable description
+}
+
+// FilterPropertiesConfig specifies schema properties to drop from the
+// filtered OpenAPI spec.
+type FilterPropertiesConfig struct {
+	Drop           map[string][]string `koanf:"drop"`           // Map of component schema names to property names to drop
+	DropExtensions []string            `koanf:"dropExtensions"` // Drop properties marked with any of these extensions (e.g. "x-internal")
+}
+
+// StripConfig specifies kinds of fields removed across the whole filtered
+// OpenAPI spec to shrink it.
+type StripConfig struct {
+	Descriptions bool     `koanf:"descriptions"` // Remove description fields
+	Summaries    bool     `koanf:"summaries"`    // Remove summary fields
+	Examples     bool     `koanf:"examples"`     // Remove example and examples fields
+	ExternalDocs bool     `koanf:"externalDocs"` // Remove externalDocs fields
+	Extensions   []string `koanf:"extensions"`   // Glob patterns of x- extensions to remove (e.g. "x-internal-*")
+	Protect      []string `koanf:"protect"`      // JSON pointer patterns of fields to keep (e.g. "/info/description", "/components/schemas/Pet/**")
+}
+
 // ToolConfig contains tool-specific configuration settings.
 type ToolConfig struct {
-	Logger *LoggerConfig `koanf:"logger"` // Logger configuration
-	Loader *LoaderConfig `koanf:"loader"` // OpenAPI loader configuration
+	Logger     *LoggerConfig     `koanf:"logger"`     // Logger configuration
+	Loader     *LoaderConfig     `koanf:"loader"`     // OpenAPI loader configuration
+	Validation *ValidationConfig `koanf:"validation"` // Filtered spec validation configuration
 }
 
 // LoggerConfig defines the logging configuration for the tool.
@@ -50,3 +208,9 @@ type LoggerConfig struct {
 type LoaderConfig struct {
 	IsExternalRefsAllowed bool `koanf:"external_refs_allowed"` // Whether to allow external references
 }
+
+// ValidationConfig defines validation of the filtered spec before it is written.
+type ValidationConfig struct {
+	Enabled  bool `koanf:"enabled"`   // Whether to validate the filtered spec
+	WarnOnly bool `koanf:"warn_only"` // Report violations as warnings instead of failing
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigBaseName is the base name of the config file looked up by [FindConfig].
+const ConfigBaseName = ".openapi-filter"
+
+// ConfigExtensions lists supported config file extensions in lookup order.
+var ConfigExtensions = []string{"yaml", "yml", "toml", "json"}
+
+var ErrConfigNotFound = errors.New("config not found")
+
+// FindConfig looks for a config file in dir and its parent directories,
+// trying every supported extension. The lookup stops at the repository
+// root (a directory containing .git) or the filesystem root.
+// Returns [ErrConfigNotFound] if there is no config file.
+func FindConfig(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("filepath.Abs: %w", err)
+	}
+	for {
+		for _, ext := range ConfigExtensions {
+			configPath := filepath.Join(dir, ConfigBaseName+"."+ext)
+			if isFile(configPath) {
+				return configPath, nil
+			}
+		}
+		if exists(filepath.Join(dir, ".git")) {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return "", ErrConfigNotFound
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
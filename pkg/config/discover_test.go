@@ -0,0 +1,129 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string // Files under the temp dir, dirs end with "/"
+		dir   string   // Dir to start the lookup in
+		want  string   // Found config, "" if not found
+	}{
+		{
+			name:  "in dir",
+			files: []string{"repo/.git/", "repo/.openapi-filter.yaml"},
+			dir:   "repo",
+			want:  "repo/.openapi-filter.yaml",
+		},
+		{
+			name:  "in parent dir",
+			files: []string{"repo/.git/", "repo/.openapi-filter.toml", "repo/api/v1/"},
+			dir:   "repo/api/v1",
+			want:  "repo/.openapi-filter.toml",
+		},
+		{
+			name:  "closest dir first",
+			files: []string{"repo/.git/", "repo/.openapi-filter.yaml", "repo/api/.openapi-filter.json", "repo/api/v1/"},
+			dir:   "repo/api/v1",
+			want:  "repo/api/.openapi-filter.json",
+		},
+		{
+			name:  "yaml first",
+			files: []string{"repo/.git/", "repo/.openapi-filter.json", "repo/.openapi-filter.toml", "repo/.openapi-filter.yml", "repo/.openapi-filter.yaml"},
+			dir:   "repo",
+			want:  "repo/.openapi-filter.yaml",
+		},
+		{
+			name:  "yml before toml",
+			files: []string{"repo/.git/", "repo/.openapi-filter.json", "repo/.openapi-filter.toml", "repo/.openapi-filter.yml"},
+			dir:   "repo",
+			want:  "repo/.openapi-filter.yml",
+		},
+		{
+			name:  "toml before json",
+			files: []string{"repo/.git/", "repo/.openapi-filter.json", "repo/.openapi-filter.toml"},
+			dir:   "repo",
+			want:  "repo/.openapi-filter.toml",
+		},
+		{
+			name:  "directories ignored",
+			files: []string{"repo/.git/", "repo/.openapi-filter.yaml/", "repo/.openapi-filter.json"},
+			dir:   "repo",
+			want:  "repo/.openapi-filter.json",
+		},
+		{
+			name:  "stops at repository root",
+			files: []string{".openapi-filter.yaml", "repo/.git/", "repo/api/"},
+			dir:   "repo/api",
+		},
+		{
+			name:  "stops at worktree root",
+			files: []string{".openapi-filter.yaml", "repo/.git", "repo/api/"},
+			dir:   "repo/api",
+		},
+		{
+			name:  "not found",
+			files: []string{"repo/.git/", "repo/api/"},
+			dir:   "repo/api",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, file := range tt.files {
+				path := filepath.Join(root, file)
+				if file[len(file)-1] == '/' {
+					if err := os.MkdirAll(path, 0o755); err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, nil, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := FindConfig(filepath.Join(root, tt.dir))
+			if tt.want == "" {
+				if !errors.Is(err, ErrConfigNotFound) {
+					t.Fatalf("FindConfig() = %q, %v, want %v", got, err, ErrConfigNotFound)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindConfig() error = %v", err)
+			}
+			if want := filepath.Join(root, tt.want); got != want {
+				t.Errorf("FindConfig() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestFindConfigRelativeDir(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(root, ".openapi-filter.yml")
+	if err := os.WriteFile(want, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(root)
+
+	got, err := FindConfig(".")
+	if err != nil {
+		t.Fatalf("FindConfig() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("FindConfig() = %q, want %q", got, want)
+	}
+}
@@ -4,38 +4,140 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/knadh/koanf/parsers/json"
 	"github.com/knadh/koanf/parsers/toml/v2"
 	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
 )
 
 var ErrConfigPathEmpty = errors.New("config path is empty")
 
-func initConfig[C any](configPath string) (*C, error) {
-	k := koanf.New(".")
+// defaults are loaded before the config file.
+var defaults = map[string]any{
+	toolKey: map[string]any{
+		"logger": map[string]any{
+			"level": "info",
+		},
+	},
+}
+
+// LoadOption configures additional config sources applied on top of
+// the config file.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	envPrefix  string
+	unknownEnv func(names []string)
+	overrides  []string
+}
+
+// WithEnv enables overriding config values from environment variables
+// with the given prefix (see [EnvPrefix]).
+func WithEnv(prefix string) LoadOption {
+	return func(o *loadOptions) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithUnknownEnvHandler sets a function called with the sorted names of
+// environment variables that have the env prefix but match no config key,
+// e.g. to warn about typos. Such variables are ignored.
+func WithUnknownEnvHandler(fn func(names []string)) LoadOption {
+	return func(o *loadOptions) {
+		o.unknownEnv = fn
+	}
+}
 
+// WithOverrides sets config values from "key=value" pairs, where key is
+// a dot-delimited config key (e.g. "x-openapi-filter.logger.level=debug").
+// List values are comma-separated.
+func WithOverrides(overrides ...string) LoadOption {
+	return func(o *loadOptions) {
+		o.overrides = append(o.overrides, overrides...)
+	}
+}
+
+func configParser(configPath string) (koanf.Parser, error) {
 	configExt := strings.TrimLeft(filepath.Ext(configPath), ".")
 
-	var parser koanf.Parser
 	switch configExt {
 	case "yaml", "yml":
-		parser = yaml.Parser()
+		return yaml.Parser(), nil
 	case "toml":
-		parser = toml.Parser()
+		return toml.Parser(), nil
 	case "json":
-		parser = json.Parser()
+		return json.Parser(), nil
 	default:
 		return nil, fmt.Errorf("unsupported config format: %s", configExt)
 	}
+}
+
+// filterKeys are keys selecting elements to keep. Without a config file,
+// the spec is kept as is unless one of them is set by env or overrides.
+var filterKeys = []string{"paths", "select", "components"}
+
+// initConfig loads the config with the following precedence, from lowest
+// to highest: defaults, config file, environment variables, overrides.
+// If configPath is empty and no filter keys are set by env or overrides,
+// the config is loaded in pass-through mode.
+func initConfig[C any](configPath string, opts ...LoadOption) (*C, error) {
+	var o loadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	typ := reflect.TypeFor[C]()
+	k := koanf.New(".")
+
+	if err := k.Load(confmap.Provider(defaults, ""), nil); err != nil {
+		return nil, fmt.Errorf("k.Load(defaults): %w", err)
+	}
+
+	if configPath != "" {
+		parser, err := configParser(configPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := k.Load(file.Provider(configPath), parser); err != nil {
+			return nil, fmt.Errorf("k.Load: %w", err)
+		}
+	}
 
-	if err := k.Load(file.Provider(configPath), parser); err != nil {
-		return nil, fmt.Errorf("k.Load: %w", err)
+	if o.envPrefix != "" {
+		unknown, err := loadEnv(k, typ, o.envPrefix)
+		if err != nil {
+			return nil, err
+		}
+		if len(unknown) > 0 && o.unknownEnv != nil {
+			o.unknownEnv(unknown)
+		}
 	}
 
+	if len(o.overrides) > 0 {
+		overrides, err := parseOverrides(typ, o.overrides)
+		if err != nil {
+			return nil, fmt.Errorf("parseOverrides: %w", err)
+		}
+		if err := k.Load(confmap.Provider(overrides, ""), nil); err != nil {
+			return nil, fmt.Errorf("k.Load(overrides): %w", err)
+		}
+	}
+
+	if configPath == "" && !k.Exists("passThrough") &&
+		!slices.ContainsFunc(filterKeys, k.Exists) {
+		_ = k.Set("passThrough", true)
+	}
+
+	normalizeToggles(k, typ)
+	normalizeListToggles(k, typ)
+
 	var cfg C
 	if err := k.Unmarshal("", &cfg); err != nil {
 		return nil, fmt.Errorf("k.Unmarshal: %w", err)
@@ -43,11 +145,66 @@ func initConfig[C any](configPath string) (*C, error) {
 	return &cfg, nil
 }
 
-func LoadConfig(configPath string) (*Config, error) {
+// loadEnv loads config values from environment variables with the prefix
+// and returns the sorted names of those matching no config key.
+func loadEnv(k *koanf.Koanf, typ reflect.Type, prefix string) ([]string, error) {
+	keys := envKeys(typ)
+	var unknown []string
+	provider := env.ProviderWithValue(prefix, ".", func(name, value string) (string, any) {
+		ck, ok := keys[strings.TrimPrefix(name, prefix)]
+		if !ok {
+			unknown = append(unknown, name)
+			return "", nil
+		}
+		return strings.Join(ck.path, "."), ck.parseValue(value)
+	})
+	if err := k.Load(provider, nil); err != nil {
+		return nil, fmt.Errorf("k.Load(env): %w", err)
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+func LoadConfig(configPath string, opts ...LoadOption) (*Config, error) {
 	if configPath == "" {
 		return nil, ErrConfigPathEmpty
 	}
-	cfg, err := initConfig[Config](configPath)
+	cfg, err := initConfig[Config](configPath, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("initConfig[Config]: %w", err)
+	}
+	cfg.resolvePaths(filepath.Dir(configPath))
+	return cfg, nil
+}
+
+// resolvePaths makes relative file paths of the config relative to dir,
+// the directory of the config file, instead of the working directory.
+func (c *Config) resolvePaths(dir string) {
+	c.FilterConfig.resolvePaths(dir)
+	for i := range c.Inputs {
+		c.Inputs[i].Spec = resolvePath(dir, c.Inputs[i].Spec)
+		c.Inputs[i].FilterConfig.resolvePaths(dir)
+	}
+}
+
+func (c *FilterConfig) resolvePaths(dir string) {
+	for i, path := range c.Overlays {
+		c.Overlays[i] = resolvePath(dir, path)
+	}
+}
+
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// LoadDefaultConfig loads the config used when there is no config file.
+// The returned config is in pass-through mode, i.e. the spec is kept as
+// is, unless paths, select or components are set by env or overrides.
+func LoadDefaultConfig(opts ...LoadOption) (*Config, error) {
+	cfg, err := initConfig[Config]("", opts...)
 	if err != nil {
 		return nil, fmt.Errorf("initConfig[Config]: %w", err)
 	}
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestLoadConfigResolvesPaths(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".openapi-filter.yaml")
+	data := `
+overlays: [ overlays/partner.yaml, /abs/overlay.yaml ]
+inputs:
+  - spec: billing.yaml
+    overlays: [ billing-overlay.yaml ]
+`
+	if err := os.WriteFile(configPath, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	wantOverlays := []string{filepath.Join(dir, "overlays/partner.yaml"), "/abs/overlay.yaml"}
+	if !slices.Equal(cfg.Overlays, wantOverlays) {
+		t.Errorf("Overlays = %v, want %v", cfg.Overlays, wantOverlays)
+	}
+	if want := filepath.Join(dir, "billing.yaml"); cfg.Inputs[0].Spec != want {
+		t.Errorf("Inputs[0].Spec = %q, want %q", cfg.Inputs[0].Spec, want)
+	}
+	wantInputOverlays := []string{filepath.Join(dir, "billing-overlay.yaml")}
+	if !slices.Equal(cfg.Inputs[0].Overlays, wantInputOverlays) {
+		t.Errorf("Inputs[0].Overlays = %v, want %v", cfg.Inputs[0].Overlays, wantInputOverlays)
+	}
+}
+
+func TestLoadDefaultConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		overrides       []string
+		wantPassThrough bool
+		wantPaths       map[string][]string
+	}{
+		{name: "no overrides", wantPassThrough: true},
+		{name: "unrelated override", overrides: []string{"x-openapi-filter.logger.level=debug"}, wantPassThrough: true},
+		{name: "paths override", overrides: []string{"paths./pets=get"},
+			wantPaths: map[string][]string{"/pets": {"get"}}},
+		{name: "select override", overrides: []string{"select.tags=pet"}},
+		{name: "components override", overrides: []string{"components.schemas=Pet"}},
+		{name: "explicit pass-through", overrides: []string{"paths./pets=get", "passThrough=true"},
+			wantPassThrough: true, wantPaths: map[string][]string{"/pets": {"get"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadDefaultConfig(WithOverrides(tt.overrides...))
+			if err != nil {
+				t.Fatalf("LoadDefaultConfig() error = %v", err)
+			}
+			if cfg.PassThrough != tt.wantPassThrough {
+				t.Errorf("PassThrough = %v, want %v", cfg.PassThrough, tt.wantPassThrough)
+			}
+			if tt.wantPaths != nil && !reflect.DeepEqual(cfg.Paths, tt.wantPaths) {
+				t.Errorf("Paths = %v, want %v", cfg.Paths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestLoadConfigSources(t *testing.T) {
+	const file = `
+x-openapi-filter:
+  logger: {level: warn}
+  validation: {warn_only: true}
+paths:
+  /pets: [get]
+components:
+  schemas: [Pet]
+`
+	tests := []struct {
+		name      string
+		env       map[string]string
+		overrides []string
+		get       func(*Config) any
+		want      any
+	}{
+		{name: "file", get: logLevel, want: "warn"},
+		{name: "env", env: map[string]string{"OPENAPI_FILTER_LOGGER_LEVEL": "debug"},
+			get: logLevel, want: "debug"},
+		{name: "env with tool section name", env: map[string]string{"OPENAPI_FILTER_X_OPENAPI_FILTER_LOGGER_LEVEL": "debug"},
+			get: logLevel, want: "debug"},
+		{name: "overrides over env", env: map[string]string{"OPENAPI_FILTER_LOGGER_LEVEL": "debug"},
+			overrides: []string{"x-openapi-filter.logger.level=error"}, get: logLevel, want: "error"},
+		{name: "env list", env: map[string]string{"OPENAPI_FILTER_COMPONENTS_SCHEMAS": "Pet, User"},
+			get: func(cfg *Config) any { return cfg.Components.Schemas }, want: []string{"Pet", "User"}},
+		{name: "override map key with dots", overrides: []string{"paths./v1.0/users=get,post"},
+			get:  func(cfg *Config) any { return cfg.Paths },
+			want: map[string][]string{"/pets": {"get"}, "/v1.0/users": {"get", "post"}}},
+		{name: "toggle section in file", get: validation,
+			want: ValidationConfig{Enabled: true, WarnOnly: true}},
+		{name: "toggle disabled by env", env: map[string]string{"OPENAPI_FILTER_VALIDATION": "false"},
+			get: validation, want: ValidationConfig{WarnOnly: true}},
+		{name: "toggle disabled by override", overrides: []string{"x-openapi-filter.validation=false"},
+			get: validation, want: ValidationConfig{WarnOnly: true}},
+		{name: "bool by env", env: map[string]string{"OPENAPI_FILTER_SERVERS": "true"},
+			get: func(cfg *Config) any { return cfg.Servers }, want: true},
+		{name: "server rules by env and override",
+			env:       map[string]string{"OPENAPI_FILTER_SERVER_RULES_URLS": "https://*.example.com/*"},
+			overrides: []string{"serverRules.environments=production,staging"},
+			get:       func(cfg *Config) any { return cfg.ServerRules },
+			want: ServersConfig{URLs: []string{"https://*.example.com/*"},
+				Environments: []string{"production", "staging"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), ".openapi-filter.yaml")
+			if err := os.WriteFile(configPath, []byte(file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			cfg, err := LoadConfig(configPath, WithEnv(EnvPrefix), WithOverrides(tt.overrides...))
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if got := tt.get(cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigUnknownEnv(t *testing.T) {
+	t.Setenv("OPENAPI_FILTER_FOO", "1")
+	t.Setenv("OPENAPI_FILTER_LOGGER_LEVEL", "debug")
+	t.Setenv("OPENAPI_FILTER_BAR", "1")
+
+	var unknown []string
+	cfg, err := LoadDefaultConfig(WithEnv(EnvPrefix),
+		WithUnknownEnvHandler(func(names []string) { unknown = names }))
+	if err != nil {
+		t.Fatalf("LoadDefaultConfig() error = %v", err)
+	}
+	if got := logLevel(cfg); got != "debug" {
+		t.Errorf("logger level = %v, want debug", got)
+	}
+	if want := []string{"OPENAPI_FILTER_BAR", "OPENAPI_FILTER_FOO"}; !slices.Equal(unknown, want) {
+		t.Errorf("unknown env = %v, want %v", unknown, want)
+	}
+}
+
+func logLevel(cfg *Config) any {
+	return cfg.Tool.Logger.Level
+}
+
+func validation(cfg *Config) any {
+	return *cfg.Tool.Validation
+}
@@ -0,0 +1,294 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// EnvPrefix is the prefix of environment variables that override config values.
+const EnvPrefix = "OPENAPI_FILTER_"
+
+const (
+	toolKey    = "x-openapi-filter"
+	enabledKey = "enabled"
+)
+
+var ErrUnknownConfigKey = errors.New("unknown config key")
+
+// configKey describes a single settable config key: its path in the koanf
+// tree and the Go type of the value stored under it.
+type configKey struct {
+	path []string
+	typ  reflect.Type
+}
+
+// resolveKey walks the koanf tags of t following the dot-delimited key and
+// returns the path of the key. Once a map with non-nested values is reached,
+// the rest of the key is used as the map key as is, so map keys containing
+// dots (e.g. "paths./v1.0/users") are not split.
+func resolveKey(t reflect.Type, key string) (configKey, error) {
+	var path []string
+	rest := key
+	for rest != "" {
+		t = derefType(t)
+		switch t.Kind() {
+		case reflect.Struct:
+			seg, tail, _ := strings.Cut(rest, ".")
+			field, ok := fieldByTag(t, seg)
+			if !ok {
+				return configKey{}, fmt.Errorf("%w: %s", ErrUnknownConfigKey, key)
+			}
+			path, t, rest = append(path, seg), field.Type, tail
+		case reflect.Map:
+			elem := derefType(t.Elem())
+			seg, tail := rest, ""
+			if elem.Kind() == reflect.Struct || elem.Kind() == reflect.Map {
+				seg, tail, _ = strings.Cut(rest, ".")
+			}
+			path, t, rest = append(path, seg), t.Elem(), tail
+		default:
+			return configKey{}, fmt.Errorf("%w: %s", ErrUnknownConfigKey, key)
+		}
+	}
+	if len(path) == 0 {
+		return configKey{}, fmt.Errorf("%w: %s", ErrUnknownConfigKey, key)
+	}
+	if field, ok := toggleField(t); ok {
+		path, t = append(path, enabledKey), field.Type
+	}
+	return configKey{path: path, typ: t}, nil
+}
+
+// fieldByTag finds the struct field with the given koanf tag name,
+// descending into squashed embedded structs.
+func fieldByTag(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, opts, _ := strings.Cut(field.Tag.Get("koanf"), ",")
+		if opts == "squash" {
+			if f, ok := fieldByTag(derefType(field.Type), name); ok {
+				return f, true
+			}
+			continue
+		}
+		if tag == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// leafKeys lists all keys of t that can be set from a single value. Keys
+// under maps are not listed, as their names are not known in advance.
+func leafKeys(t reflect.Type, prefix []string) []configKey {
+	var keys []configKey
+	t = derefType(t)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, opts, _ := strings.Cut(field.Tag.Get("koanf"), ",")
+		if opts == "squash" {
+			keys = append(keys, leafKeys(field.Type, prefix)...)
+			continue
+		}
+		if tag == "" || tag == "-" {
+			continue
+		}
+		path := append(append([]string{}, prefix...), tag)
+		switch derefType(field.Type).Kind() {
+		case reflect.Struct:
+			keys = append(keys, leafKeys(field.Type, path)...)
+		case reflect.Map:
+		default:
+			keys = append(keys, configKey{path: path, typ: field.Type})
+		}
+	}
+	return keys
+}
+
+// toggleField returns the enabled field of a toggle section, which is a
+// section that may also be set by a single boolean (e.g. "validation: true").
+func toggleField(t reflect.Type) (reflect.StructField, bool) {
+	if t = derefType(t); t.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	return fieldByTag(t, enabledKey)
+}
+
+// normalizeToggles converts toggle sections set by a single boolean to
+// the section form, e.g. "validation: true" to "validation.enabled: true".
+// Toggle sections set in the section form are enabled by default.
+func normalizeToggles(k *koanf.Koanf, t reflect.Type) {
+	for _, ck := range leafKeys(t, nil) {
+		if ck.path[len(ck.path)-1] != enabledKey {
+			continue
+		}
+		section := strings.Join(ck.path[:len(ck.path)-1], ".")
+		switch value := k.Get(section).(type) {
+		case map[string]any:
+			if _, ok := value[enabledKey]; !ok {
+				_ = k.Set(section+"."+enabledKey, true)
+			}
+		case nil:
+		default:
+			k.Delete(section)
+			_ = k.Set(section+"."+enabledKey, value)
+		}
+	}
+}
+
+// normalizeListToggles normalizes toggle sections of list items, such as
+// input specs.
+func normalizeListToggles(k *koanf.Koanf, t reflect.Type) {
+	t = derefType(t)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("koanf"), ",")
+		if field.Type.Kind() != reflect.Slice || derefType(field.Type.Elem()).Kind() != reflect.Struct {
+			continue
+		}
+		items, ok := k.Get(tag).([]any)
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			if m, ok := item.(map[string]any); ok {
+				normalizeTogglesMap(m, field.Type.Elem())
+			}
+		}
+		k.Delete(tag)
+		_ = k.Set(tag, items)
+	}
+}
+
+// normalizeTogglesMap is normalizeToggles for a section given as a map.
+func normalizeTogglesMap(m map[string]any, t reflect.Type) {
+	t = derefType(t)
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, opts, _ := strings.Cut(field.Tag.Get("koanf"), ",")
+		if opts == "squash" {
+			normalizeTogglesMap(m, field.Type)
+			continue
+		}
+		value, ok := m[tag]
+		if !ok || derefType(field.Type).Kind() != reflect.Struct {
+			continue
+		}
+		section, ok := value.(map[string]any)
+		if _, isToggle := toggleField(field.Type); isToggle {
+			if !ok {
+				m[tag] = map[string]any{enabledKey: value}
+				continue
+			}
+			if _, ok := section[enabledKey]; !ok {
+				section[enabledKey] = true
+			}
+		}
+		if ok {
+			normalizeTogglesMap(section, field.Type)
+		}
+	}
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// parseValue converts a raw string into the value stored under the key.
+// Lists are comma-separated, other values are left to the weakly typed
+// decoding of koanf.
+func (ck configKey) parseValue(raw string) any {
+	if derefType(ck.typ).Kind() != reflect.Slice {
+		return raw
+	}
+	values := []string{}
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// envName converts a key path to the environment variable name without
+// the prefix, e.g. "components.securitySchemes" -> "COMPONENTS_SECURITY_SCHEMES".
+func envName(path []string) string {
+	var s strings.Builder
+	for i, seg := range path {
+		if i > 0 {
+			s.WriteByte('_')
+		}
+		for j, r := range seg {
+			switch {
+			case r == '-' || r == '.':
+				s.WriteByte('_')
+			case unicode.IsUpper(r) && j > 0:
+				s.WriteByte('_')
+				s.WriteRune(r)
+			default:
+				s.WriteRune(unicode.ToUpper(r))
+			}
+		}
+	}
+	return s.String()
+}
+
+// envKeys maps environment variable names (without the prefix) to config
+// keys. Tool keys are available both with and without the tool section
+// name, e.g. both X_OPENAPI_FILTER_LOGGER_LEVEL and LOGGER_LEVEL.
+func envKeys(t reflect.Type) map[string]configKey {
+	keys := make(map[string]configKey)
+	for _, ck := range leafKeys(t, nil) {
+		paths := [][]string{ck.path}
+		if last := len(ck.path) - 1; ck.path[last] == enabledKey {
+			paths = append(paths, ck.path[:last]) // e.g. VALIDATION for VALIDATION_ENABLED
+		}
+		for _, path := range paths {
+			keys[envName(path)] = ck
+			if len(path) > 1 && path[0] == toolKey {
+				if short := envName(path[1:]); keys[short].path == nil {
+					keys[short] = ck
+				}
+			}
+		}
+	}
+	return keys
+}
+
+// parseOverrides parses "key=value" overrides into a nested map that can
+// be loaded on top of the config file.
+func parseOverrides(t reflect.Type, overrides []string) (map[string]any, error) {
+	m := make(map[string]any)
+	for _, o := range overrides {
+		key, value, ok := strings.Cut(o, "=")
+		if !ok {
+			return nil, fmt.Errorf("override %q is not in key=value format", o)
+		}
+		ck, err := resolveKey(t, strings.TrimSpace(key))
+		if err != nil {
+			return nil, err
+		}
+		setNested(m, ck.path, ck.parseValue(value))
+	}
+	return m, nil
+}
+
+func setNested(m map[string]any, path []string, value any) {
+	for _, seg := range path[:len(path)-1] {
+		next, ok := m[seg].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			m[seg] = next
+		}
+		m = next
+	}
+	m[path[len(path)-1]] = value
+}
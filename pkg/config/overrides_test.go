@@ -0,0 +1,229 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+)
+
+type testToggle struct {
+	Enabled bool   `koanf:"enabled"`
+	Mode    string `koanf:"mode"`
+}
+
+type testItem struct {
+	Name   string     `koanf:"name"`
+	Toggle testToggle `koanf:"toggle"`
+}
+
+type testEmbedded struct {
+	Flag bool `koanf:"flag"`
+}
+
+type testTool struct {
+	Level string     `koanf:"level"`
+	Check testToggle `koanf:"check"`
+}
+
+type testConfig struct {
+	Tool            testTool `koanf:"x-openapi-filter"`
+	testEmbedded    `koanf:",squash"`
+	Paths           map[string][]string          `koanf:"paths"`
+	Rename          map[string]map[string]string `koanf:"rename"`
+	Toggle          *testToggle                  `koanf:"toggle"`
+	SecuritySchemes []string                     `koanf:"securitySchemes"`
+	Items           []testItem                   `koanf:"items"`
+	Ignored         string                       `koanf:"-"`
+}
+
+var testConfigType = reflect.TypeFor[testConfig]()
+
+func TestResolveKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		wantPath string
+		wantKind reflect.Kind
+	}{
+		{key: "x-openapi-filter.level", wantPath: "x-openapi-filter/level", wantKind: reflect.String},
+		{key: "flag", wantPath: "flag", wantKind: reflect.Bool},
+		{key: "paths./v1.0/users", wantPath: "paths//v1.0/users", wantKind: reflect.Slice},
+		{key: "rename.schemas.Pet.V2", wantPath: "rename/schemas/Pet.V2", wantKind: reflect.String},
+		{key: "toggle", wantPath: "toggle/enabled", wantKind: reflect.Bool},
+		{key: "toggle.mode", wantPath: "toggle/mode", wantKind: reflect.String},
+		{key: "x-openapi-filter.check", wantPath: "x-openapi-filter/check/enabled", wantKind: reflect.Bool},
+		{key: "unknown"},
+		{key: "x-openapi-filter.unknown"},
+		{key: "flag.value"},
+		{key: "Ignored"},
+		{key: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			ck, err := resolveKey(testConfigType, tt.key)
+			if tt.wantPath == "" {
+				if !errors.Is(err, ErrUnknownConfigKey) {
+					t.Fatalf("resolveKey() error = %v, want %v", err, ErrUnknownConfigKey)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveKey() error = %v", err)
+			}
+			if got := strings.Join(ck.path, "/"); got != tt.wantPath {
+				t.Errorf("path = %q, want %q", got, tt.wantPath)
+			}
+			if got := derefType(ck.typ).Kind(); got != tt.wantKind {
+				t.Errorf("type kind = %v, want %v", got, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestLeafKeys(t *testing.T) {
+	var got []string
+	for _, ck := range leafKeys(testConfigType, nil) {
+		got = append(got, strings.Join(ck.path, "."))
+	}
+	want := []string{
+		"x-openapi-filter.level",
+		"x-openapi-filter.check.enabled",
+		"x-openapi-filter.check.mode",
+		"flag",
+		"toggle.enabled",
+		"toggle.mode",
+		"securitySchemes",
+		"items",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("leafKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestNormalizeToggles(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]any
+		want map[string]any
+	}{
+		{
+			name: "boolean",
+			data: map[string]any{"toggle": true, "x-openapi-filter": map[string]any{"check": false}},
+			want: map[string]any{
+				"toggle":           map[string]any{"enabled": true},
+				"x-openapi-filter": map[string]any{"check": map[string]any{"enabled": false}},
+			},
+		},
+		{
+			name: "section enabled by default",
+			data: map[string]any{"toggle": map[string]any{"mode": "strict"}},
+			want: map[string]any{"toggle": map[string]any{"enabled": true, "mode": "strict"}},
+		},
+		{
+			name: "section disabled",
+			data: map[string]any{"toggle": map[string]any{"enabled": false, "mode": "strict"}},
+			want: map[string]any{"toggle": map[string]any{"enabled": false, "mode": "strict"}},
+		},
+		{
+			name: "not set",
+			data: map[string]any{"flag": true},
+			want: map[string]any{"flag": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := koanf.New(".")
+			if err := k.Load(confmap.Provider(tt.data, ""), nil); err != nil {
+				t.Fatal(err)
+			}
+			normalizeToggles(k, testConfigType)
+			if got := k.Raw(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeToggles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeListToggles(t *testing.T) {
+	k := koanf.New(".")
+	data := map[string]any{"items": []any{
+		map[string]any{"name": "a", "toggle": true},
+		map[string]any{"name": "b", "toggle": map[string]any{"mode": "strict"}},
+		map[string]any{"name": "c"},
+	}}
+	if err := k.Load(confmap.Provider(data, ""), nil); err != nil {
+		t.Fatal(err)
+	}
+	normalizeListToggles(k, testConfigType)
+
+	var cfg testConfig
+	if err := k.Unmarshal("", &cfg); err != nil {
+		t.Fatalf("k.Unmarshal() error = %v", err)
+	}
+	want := []testItem{
+		{Name: "a", Toggle: testToggle{Enabled: true}},
+		{Name: "b", Toggle: testToggle{Enabled: true, Mode: "strict"}},
+		{Name: "c"},
+	}
+	if !reflect.DeepEqual(cfg.Items, want) {
+		t.Errorf("Items = %+v, want %+v", cfg.Items, want)
+	}
+}
+
+func TestEnvKeys(t *testing.T) {
+	keys := envKeys(testConfigType)
+	tests := map[string]string{
+		"X_OPENAPI_FILTER_LEVEL":         "x-openapi-filter.level",
+		"LEVEL":                          "x-openapi-filter.level",
+		"X_OPENAPI_FILTER_CHECK":         "x-openapi-filter.check.enabled",
+		"X_OPENAPI_FILTER_CHECK_ENABLED": "x-openapi-filter.check.enabled",
+		"CHECK_ENABLED":                  "x-openapi-filter.check.enabled",
+		"CHECK":                          "x-openapi-filter.check.enabled",
+		"CHECK_MODE":                     "x-openapi-filter.check.mode",
+		"FLAG":                           "flag",
+		"TOGGLE":                         "toggle.enabled",
+		"TOGGLE_MODE":                    "toggle.mode",
+		"SECURITY_SCHEMES":               "securitySchemes",
+	}
+	for name, want := range tests {
+		if got := strings.Join(keys[name].path, "."); got != want {
+			t.Errorf("envKeys()[%q] = %q, want %q", name, got, want)
+		}
+	}
+	for _, name := range []string{"PATHS", "RENAME", "IGNORED"} {
+		if ck, ok := keys[name]; ok {
+			t.Errorf("envKeys()[%q] = %v, want no key", name, ck.path)
+		}
+	}
+}
+
+func TestParseOverrides(t *testing.T) {
+	got, err := parseOverrides(testConfigType, []string{
+		"x-openapi-filter.level=debug",
+		"paths./v1.0/users=get, post,",
+		"toggle=true",
+		" securitySchemes =",
+	})
+	if err != nil {
+		t.Fatalf("parseOverrides() error = %v", err)
+	}
+	want := map[string]any{
+		"x-openapi-filter": map[string]any{"level": "debug"},
+		"paths":            map[string]any{"/v1.0/users": []string{"get", "post"}},
+		"toggle":           map[string]any{"enabled": "true"},
+		"securitySchemes":  []string{},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseOverrides() = %v, want %v", got, want)
+	}
+
+	if _, err := parseOverrides(testConfigType, []string{"flag"}); err == nil {
+		t.Error("parseOverrides() without value: want error")
+	}
+	if _, err := parseOverrides(testConfigType, []string{"unknown=1"}); !errors.Is(err, ErrUnknownConfigKey) {
+		t.Errorf("parseOverrides() error = %v, want %v", err, ErrUnknownConfigKey)
+	}
+}
@@ -0,0 +1,217 @@
+// Package diff provides comparison of two OpenAPI specs. It reports added
+// and removed paths, operations, parameters and components, and classifies
+// every change as breaking or not from the point of view of API clients.
+package diff
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/zguydev/openapi-filter/internal/components"
+	"github.com/zguydev/openapi-filter/internal/refs"
+)
+
+// ChangeKind is the kind of a change between two specs.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Element is the kind of a spec element that was changed.
+type Element string
+
+const (
+	ElementPath      Element = "path"
+	ElementOperation Element = "operation"
+	ElementParameter Element = "parameter"
+	ElementComponent Element = "component"
+)
+
+// Change describes a single change between two specs.
+type Change struct {
+	Kind     ChangeKind `json:"kind"`
+	Element  Element    `json:"element"`
+	Location string     `json:"location"`         // e.g. "/pets", "GET /pets", "#/components/schemas/Pet"
+	Detail   string     `json:"detail,omitempty"` // Details of a changed element
+	Breaking bool       `json:"breaking"`
+}
+
+// Result is the result of comparing two specs.
+type Result struct {
+	Changes []Change `json:"changes"`
+}
+
+// HasBreaking reports whether there is at least one breaking change.
+func (r *Result) HasBreaking() bool {
+	return slices.ContainsFunc(r.Changes, func(c Change) bool { return c.Breaking })
+}
+
+// Compare compares the base spec with the revision spec.
+//
+// Removed paths, operations, parameters and components are breaking, as
+// well as added required parameters and parameters that became required.
+// Other additions are not breaking. Operations of added or removed paths
+// are not reported separately.
+func Compare(base, revision *openapi3.T) *Result {
+	result := &Result{Changes: []Change{}}
+	result.comparePaths(base.Paths, revision.Paths)
+	result.compareComponents(base.Components, revision.Components)
+	return result
+}
+
+func (r *Result) add(kind ChangeKind, elem Element, location, detail string, breaking bool) {
+	r.Changes = append(r.Changes, Change{
+		Kind:     kind,
+		Element:  elem,
+		Location: location,
+		Detail:   detail,
+		Breaking: breaking,
+	})
+}
+
+func (r *Result) comparePaths(base, revision *openapi3.Paths) {
+	basePaths, revisionPaths := pathsMap(base), pathsMap(revision)
+	for _, path := range unionKeys(basePaths, revisionPaths) {
+		baseItem, inBase := basePaths[path]
+		revisionItem, inRevision := revisionPaths[path]
+		// Operations of added and removed paths are covered by the path change.
+		switch {
+		case !inRevision:
+			r.add(ChangeRemoved, ElementPath, path, "", true)
+		case !inBase:
+			r.add(ChangeAdded, ElementPath, path, "", false)
+		default:
+			r.compareOperations(path, baseItem, revisionItem)
+		}
+	}
+}
+
+func (r *Result) compareOperations(path string, base, revision *openapi3.PathItem) {
+	baseOps, revisionOps := operations(base), operations(revision)
+	for _, method := range unionKeys(baseOps, revisionOps) {
+		location := method + " " + path
+		baseOp, inBase := baseOps[method]
+		revisionOp, inRevision := revisionOps[method]
+		switch {
+		case !inRevision:
+			r.add(ChangeRemoved, ElementOperation, location, "", true)
+		case !inBase:
+			r.add(ChangeAdded, ElementOperation, location, "", false)
+		default:
+			r.compareParameters(location,
+				parameters(base, baseOp), parameters(revision, revisionOp))
+		}
+	}
+}
+
+func (r *Result) compareParameters(opLocation string, base, revision map[string]*openapi3.Parameter) {
+	for _, key := range unionKeys(base, revision) {
+		location := opLocation + " " + key
+		baseParam, inBase := base[key]
+		revisionParam, inRevision := revision[key]
+		switch {
+		case !inRevision:
+			r.add(ChangeRemoved, ElementParameter, location, "", true)
+		case !inBase:
+			r.add(ChangeAdded, ElementParameter, location, "", revisionParam.Required)
+		case !baseParam.Required && revisionParam.Required:
+			r.add(ChangeChanged, ElementParameter, location, "became required", true)
+		case baseParam.Required && !revisionParam.Required:
+			r.add(ChangeChanged, ElementParameter, location, "became optional", false)
+		}
+	}
+}
+
+func (r *Result) compareComponents(base, revision *openapi3.Components) {
+	for _, compTyp := range components.ComponentTypes() {
+		baseNames, revisionNames := componentNames(base, compTyp), componentNames(revision, compTyp)
+		for _, name := range unionKeys(setOf(baseNames), setOf(revisionNames)) {
+			ref := refs.ComponentRef(compTyp, name)
+			switch {
+			case !slices.Contains(revisionNames, name):
+				r.add(ChangeRemoved, ElementComponent, ref, "", true)
+			case !slices.Contains(baseNames, name):
+				r.add(ChangeAdded, ElementComponent, ref, "", false)
+			}
+		}
+	}
+}
+
+func pathsMap(paths *openapi3.Paths) map[string]*openapi3.PathItem {
+	if paths == nil {
+		return nil
+	}
+	return paths.Map()
+}
+
+func operations(pathItem *openapi3.PathItem) map[string]*openapi3.Operation {
+	if pathItem == nil {
+		return nil
+	}
+	return pathItem.Operations()
+}
+
+// parameters returns effective parameters of the operation, keyed by
+// location and name, e.g. "query limit". Operation parameters override
+// path item parameters.
+func parameters(pathItem *openapi3.PathItem, op *openapi3.Operation) map[string]*openapi3.Parameter {
+	params := make(map[string]*openapi3.Parameter)
+	for _, paramsList := range []openapi3.Parameters{pathItem.Parameters, op.Parameters} {
+		for _, paramr := range paramsList {
+			if p := paramr.Value; p != nil {
+				params[p.In+" "+p.Name] = p
+			}
+		}
+	}
+	return params
+}
+
+func componentNames(comps *openapi3.Components, typ components.ComponentType) []string {
+	if comps == nil {
+		return nil
+	}
+	return components.ComponentNames(comps, typ)
+}
+
+func setOf(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+func unionKeys[M ~map[string]V, V any](a, b M) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// String returns a human-readable representation of the change.
+func (c Change) String() string {
+	var s strings.Builder
+	s.WriteString(string(c.Kind))
+	s.WriteByte(' ')
+	s.WriteString(string(c.Element))
+	s.WriteByte(' ')
+	s.WriteString(c.Location)
+	if c.Detail != "" {
+		s.WriteString(": ")
+		s.WriteString(c.Detail)
+	}
+	return s.String()
+}
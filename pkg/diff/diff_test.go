@@ -0,0 +1,59 @@
+package diff
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestCompare(t *testing.T) {
+	base := loadSpec(t, `
+openapi: 3.0.3
+info: {title: Base, version: "1"}
+paths:
+  /pets:
+    get:
+      parameters: [{name: limit, in: query, schema: {type: integer}}]
+      responses: {"200": {description: OK}}
+    delete:
+      responses: {"200": {description: OK}}
+  /users:
+    get:
+      responses: {"200": {description: OK}}
+    post:
+      responses: {"200": {description: OK}}
+`)
+	revision := loadSpec(t, `
+openapi: 3.0.3
+info: {title: Revision, version: "1"}
+paths:
+  /pets:
+    get:
+      parameters: [{name: limit, in: query, required: true, schema: {type: integer}}]
+      responses: {"200": {description: OK}}
+  /orders:
+    get:
+      responses: {"200": {description: OK}}
+`)
+
+	want := []Change{
+		{Kind: ChangeAdded, Element: ElementPath, Location: "/orders"},
+		{Kind: ChangeRemoved, Element: ElementOperation, Location: "DELETE /pets", Breaking: true},
+		{Kind: ChangeChanged, Element: ElementParameter, Location: "GET /pets query limit", Detail: "became required", Breaking: true},
+		{Kind: ChangeRemoved, Element: ElementPath, Location: "/users", Breaking: true},
+	}
+	got := Compare(base, revision).Changes
+	if !slices.Equal(got, want) {
+		t.Errorf("Compare() changes:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func loadSpec(t *testing.T, data string) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(data))
+	if err != nil {
+		t.Fatalf("LoadFromData() error = %v", err)
+	}
+	return doc
+}
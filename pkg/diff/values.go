@@ -0,0 +1,127 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValueChange describes a difference between two generic documents
+// (e.g. decoded YAML or JSON) at the location given by a JSON pointer.
+type ValueChange struct {
+	Kind    ChangeKind `json:"kind"`
+	Pointer string     `json:"pointer"`
+	Old     any        `json:"old,omitempty"`
+	New     any        `json:"new,omitempty"`
+}
+
+// Values compares two generic documents semantically, ignoring key order,
+// and returns the differences sorted by location.
+func Values(old, new any) []ValueChange {
+	var changes []ValueChange
+	compareValues(&changes, "", old, new)
+	return changes
+}
+
+func compareValues(changes *[]ValueChange, pointer string, old, new any) {
+	oldMap, oldIsMap := toMap(old)
+	newMap, newIsMap := toMap(new)
+	if oldIsMap && newIsMap {
+		keys := unionKeys(oldMap, newMap)
+		for _, key := range keys {
+			oldValue, inOld := oldMap[key]
+			newValue, inNew := newMap[key]
+			child := pointer + "/" + escapePointer(key)
+			switch {
+			case !inNew:
+				*changes = append(*changes, ValueChange{Kind: ChangeRemoved, Pointer: child, Old: oldValue})
+			case !inOld:
+				*changes = append(*changes, ValueChange{Kind: ChangeAdded, Pointer: child, New: newValue})
+			default:
+				compareValues(changes, child, oldValue, newValue)
+			}
+		}
+		return
+	}
+
+	oldList, oldIsList := old.([]any)
+	newList, newIsList := new.([]any)
+	if oldIsList && newIsList {
+		for i := range max(len(oldList), len(newList)) {
+			child := fmt.Sprintf("%s/%d", pointer, i)
+			switch {
+			case i >= len(newList):
+				*changes = append(*changes, ValueChange{Kind: ChangeRemoved, Pointer: child, Old: oldList[i]})
+			case i >= len(oldList):
+				*changes = append(*changes, ValueChange{Kind: ChangeAdded, Pointer: child, New: newList[i]})
+			default:
+				compareValues(changes, child, oldList[i], newList[i])
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, ValueChange{Kind: ChangeChanged, Pointer: pointer, Old: old, New: new})
+	}
+}
+
+// toMap converts maps decoded from YAML or JSON to map[string]any.
+func toMap(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, true
+	case map[any]any:
+		converted := make(map[string]any, len(m))
+		for k, v := range m {
+			converted[fmt.Sprint(k)] = v
+		}
+		return converted, true
+	default:
+		return nil, false
+	}
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// String returns a human-readable representation of the change.
+func (c ValueChange) String() string {
+	pointer := c.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s: %s", pointer, summarize(c.New))
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s: %s", pointer, summarize(c.Old))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", pointer, summarize(c.Old), summarize(c.New))
+	}
+}
+
+// summarize formats a value in one line, truncating long values.
+func summarize(v any) string {
+	const maxLen = 80
+	var s string
+	if m, ok := toMap(v); ok {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		s = "{" + strings.Join(keys, ", ") + "}"
+	} else if data, err := json.Marshal(v); err == nil {
+		s = string(data)
+	} else {
+		s = fmt.Sprint(v)
+	}
+	if len(s) > maxLen {
+		s = s[:maxLen-3] + "..."
+	}
+	return s
+}
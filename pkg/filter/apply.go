@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/refs"
+	"github.com/zguydev/openapi-filter/internal/utils"
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+// Option configures [Apply].
+type Option func(*options)
+
+type options struct {
+	logger *zap.Logger
+	stages []customStage
+}
+
+// WithLogger sets the logger of filtering steps. Nothing is logged by
+// default or if logger is nil.
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *options) {
+		if logger == nil {
+			logger = zap.NewNop()
+		}
+		o.logger = logger
+	}
+}
+
+// WithSlogLogger sets the [slog.Logger] of filtering steps. Nothing is
+// logged if logger is nil.
+func WithSlogLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		if logger == nil {
+			o.logger = zap.NewNop()
+			return
+		}
+		o.logger = zap.New(utils.NewSlogCore(logger.Handler()))
+	}
+}
+
+// Result is the result of filtering a spec by [Apply].
+type Result struct {
+	Spec   *openapi3.T // Filtered spec
+	Report *Report     // Kept and dropped operations and components
+
+	collector *refs.RefsCollector
+}
+
+// Explain returns the shortest chain of refs from a selector to the
+// component ref, starting with the selector and ending with ref, e.g.
+// ["GET /pets", "#/components/schemas/Pets", "#/components/schemas/Pet"].
+// The ref may be given in a short form, e.g. "schemas/Pet".
+// Returns nil if the component was not pulled in by any selector.
+func (r *Result) Explain(ref string) []string {
+	return r.collector.Chain(refs.NormalizeRef(ref))
+}
+
+// Apply filters the spec with the filter config. The filtered spec is
+// made of a deep copy of doc, so doc is not modified and shares nothing
+// with the filtered spec. Apply keeps no state between calls, so it may
+// be called concurrently, also with the same doc.
+// Returns ctx.Err() if ctx is done before filtering is complete.
+func Apply(ctx context.Context, doc *openapi3.T, cfg *config.FilterConfig, opts ...Option) (*Result, error) {
+	o := options{logger: zap.NewNop()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	f := &filterer{
+		cfg:            cfg,
+		logger:         o.logger,
+		collector:      refs.NewRefsCollector(),
+		source:         doc,
+		doc:            doc,
+		rewrittenPaths: make(map[string]string),
+	}
+	stages, err := f.stages(o.stages)
+	if err != nil {
+		return nil, fmt.Errorf("stages: %w", err)
+	}
+	if err := f.run(ctx, stages); err != nil {
+		return nil, err
+	}
+	return &Result{
+		Spec:      f.filtered,
+		Report:    f.report(),
+		collector: f.collector,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+func TestApplyNilLogger(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{name: "zap", opt: WithLogger((*zap.Logger)(nil))},
+		{name: "slog", opt: WithSlogLogger((*slog.Logger)(nil))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.FilterConfig{Paths: map[string][]string{"/pets": {"get"}, "/missing": {"get"}}}
+			if _, err := Apply(context.Background(), loadTestSpec(t, mergeFirstSpec), cfg, tt.opt); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestApplyDoesNotModifySource(t *testing.T) {
+	const spec = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+servers: [{url: https://api.example.com}, {url: https://staging.example.com}]
+paths:
+  /pets:
+    get:
+      servers: [{url: https://pets.example.com}, {url: https://pets.staging.example.com}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+        internal: {type: string}
+`
+	rules := config.ServersConfig{URLs: []string{"https://api.example.com", "https://pets.example.com"}}
+	tests := []struct {
+		name string
+		cfg  *config.FilterConfig
+	}{
+		{name: "filter", cfg: &config.FilterConfig{Paths: map[string][]string{"/pets": {"get"}}, Servers: true, ServerRules: rules}},
+		{name: "pass-through", cfg: &config.FilterConfig{PassThrough: true, ServerRules: rules}},
+		{name: "prune", cfg: &config.FilterConfig{PassThrough: true, PruneComponents: true, ServerRules: rules}},
+		{name: "stage before select", cfg: &config.FilterConfig{
+			Paths:       map[string][]string{"/pets": {"get"}},
+			Servers:     true,
+			ServerRules: rules,
+			Properties:  &config.FilterPropertiesConfig{Drop: map[string][]string{"Pet": {"internal"}}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := loadTestSpec(t, spec)
+			want, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			tt.cfg.Rename = &config.RenameConfig{Components: map[string]map[string]string{"schemas": {"Pet": "Animal"}}}
+			result, err := Apply(context.Background(), doc, tt.cfg)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if got, _ := json.Marshal(doc); string(got) != string(want) {
+				t.Errorf("source changed: %s, want %s", got, want)
+			}
+
+			op := result.Spec.Paths.Value("/pets").Get
+			if op == doc.Paths.Value("/pets").Get {
+				t.Error("filtered spec shares the operation with the source")
+			}
+			if got := *op.Servers; len(got) != 1 || got[0].URL != "https://pets.example.com" {
+				t.Errorf("operation servers = %v, want only https://pets.example.com", got)
+			}
+		})
+	}
+}
@@ -4,23 +4,29 @@
 package filter
 
 import (
+	"context"
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"go.uber.org/zap"
 
 	"github.com/zguydev/openapi-filter/internal/components"
+	"github.com/zguydev/openapi-filter/internal/deepcopy"
+	"github.com/zguydev/openapi-filter/internal/expr"
 	"github.com/zguydev/openapi-filter/internal/refs"
 	"github.com/zguydev/openapi-filter/pkg/config"
 )
 
-// OpenAPISpecFilter is the main type that handles filtering of OpenAPI specs.
+// OpenAPISpecFilter filters OpenAPI specs with the filters of a config.
+// It keeps the result of the last [OpenAPISpecFilter.Filter] call for
+// [OpenAPISpecFilter.Report] and [OpenAPISpecFilter.Explain], so unlike
+// [Apply] it must not be used concurrently.
 type OpenAPISpecFilter struct {
-	cfg       *config.FilterConfig
-	logger    *zap.Logger
-	collector *refs.RefsCollector
-
-	doc, filtered *openapi3.T
+	cfg    *config.FilterConfig
+	logger *zap.Logger
+	result *Result
 }
 
 // NewOpenAPISpecFilter creates a new OpenAPISpecFilter instance with the
@@ -30,9 +36,8 @@ func NewOpenAPISpecFilter(
 	logger *zap.Logger,
 ) *OpenAPISpecFilter {
 	return &OpenAPISpecFilter{
-		cfg:       &cfg.FilterConfig,
-		logger:    logger,
-		collector: refs.NewRefsCollector(),
+		cfg:    &cfg.FilterConfig,
+		logger: logger,
 	}
 }
 
@@ -40,65 +45,260 @@ func NewOpenAPISpecFilter(
 // filters and returns a filtered spec.
 // Returns an error if any step of the filtering process fails.
 func (oaf *OpenAPISpecFilter) Filter(doc *openapi3.T) (filtered *openapi3.T, err error) {
-	oaf.doc = doc
+	result, err := Apply(context.Background(), doc, oaf.cfg, WithLogger(oaf.logger))
+	if err != nil {
+		return nil, err
+	}
+	oaf.result = result
+	return result.Spec, nil
+}
+
+// Report returns the report of the last [OpenAPISpecFilter.Filter] call.
+func (oaf *OpenAPISpecFilter) Report() *Report {
+	if oaf.result == nil {
+		return newReport()
+	}
+	return oaf.result.Report
+}
+
+// Explain is [Result.Explain] of the last [OpenAPISpecFilter.Filter] call.
+func (oaf *OpenAPISpecFilter) Explain(ref string) []string {
+	if oaf.result == nil {
+		return nil
+	}
+	return oaf.result.Explain(ref)
+}
+
+// filterer holds the state of a single filtering of a spec.
+type filterer struct {
+	cfg       *config.FilterConfig
+	logger    *zap.Logger
+	collector *refs.RefsCollector
+
+	source         *openapi3.T // Spec to filter, never modified
+	doc            *openapi3.T // Source, or its deep copy once a stage before select modifies it
+	filtered       *openapi3.T
+	rewrittenPaths map[string]string // Source path -> rewritten path
+	renamed        componentNames    // Source component name -> new name
+}
+
+// selectSpec makes the filtered spec of the spec, according to the mode
+// set in the configuration.
+func (f *filterer) selectSpec() error {
+	switch {
+	case f.cfg.PassThrough && f.cfg.PruneComponents:
+		f.logger.Info("pass-through mode, pruning unused components")
+		f.pruneComponents()
+	case f.cfg.PassThrough:
+		f.logger.Info("pass-through mode, keeping spec as is")
+		f.passThrough()
+	default:
+		if err := f.filterSpec(); err != nil {
+			return err
+		}
+	}
+	if f.doc == f.source {
+		// No stage has copied the source spec, so only the selected
+		// elements are copied.
+		f.filtered = deepcopy.Copy(f.filtered)
+	}
+	f.filterServers()
+	return nil
+}
 
-	oaf.filtered = &openapi3.T{
-		OpenAPI:    oaf.doc.OpenAPI,
+// filterSpec filters the spec by paths, components and top-level elements
+// selected in the configuration. The filtered spec shares the selected
+// elements with the spec.
+func (f *filterer) filterSpec() error {
+	f.filtered = &openapi3.T{
+		OpenAPI:    f.doc.OpenAPI,
 		Components: &openapi3.Components{},
-		Info:       oaf.doc.Info,
+		Info:       f.doc.Info,
 		Paths:      &openapi3.Paths{},
 	}
 
-	oaf.filterPaths()
-	oaf.filterComponents()
-	oaf.filterOther()
-	oaf.filterRefs()
-	if components.IsEmptyComponents(oaf.filtered.Components) {
-		oaf.filtered.Components = nil
+	if err := f.filterPaths(); err != nil {
+		return fmt.Errorf("filterPaths: %w", err)
+	}
+	f.filterComponents()
+	f.filterOther()
+	f.filterRefs()
+	if components.IsEmptyComponents(f.filtered.Components) {
+		f.filtered.Components = nil
+	}
+	return nil
+}
+
+// passThrough keeps all elements of the source spec.
+func (f *filterer) passThrough() {
+	f.filtered = &openapi3.T{
+		Extensions:   f.doc.Extensions,
+		OpenAPI:      f.doc.OpenAPI,
+		Components:   f.doc.Components,
+		Info:         f.doc.Info,
+		Paths:        f.doc.Paths,
+		Security:     f.doc.Security,
+		Servers:      f.doc.Servers,
+		Tags:         f.doc.Tags,
+		ExternalDocs: f.doc.ExternalDocs,
+	}
+}
+
+// pruneComponents keeps all elements of the source spec, except for
+// components not reachable from any operation (including its callbacks),
+// path item or component selected in the configuration.
+// Security schemes used by security requirements are kept as well.
+func (f *filterer) pruneComponents() {
+	f.passThrough()
+	f.filtered.Components = &openapi3.Components{}
+	if f.doc.Components != nil {
+		f.filtered.Components.Extensions = f.doc.Components.Extensions
+	}
+
+	f.collector.SetOrigin("security")
+	f.collector.CollectSecurityRequirements(f.doc.Security)
+	for _, path := range sortedKeys(f.doc.Paths.Map()) {
+		pathItem := f.doc.Paths.Value(path)
+		f.collector.SetOrigin(path)
+		f.collector.CollectParameters(pathItem.Parameters)
+		for method, op := range pathItem.Operations() {
+			f.collector.SetOrigin(OperationSelector(method, path))
+			f.collector.CollectOperation(op)
+			if op.Security != nil {
+				f.collector.CollectSecurityRequirements(*op.Security)
+			}
+		}
+	}
+	f.filterComponents()
+	f.filterRefs()
+
+	if f.doc.Components != nil {
+		for _, compTyp := range components.ComponentTypes() {
+			kept := components.ComponentNames(f.filtered.Components, compTyp)
+			for _, name := range components.ComponentNames(f.doc.Components, compTyp) {
+				if !slices.Contains(kept, name) {
+					f.logger.Info("pruned unused component",
+						zap.String("ref", refs.ComponentRef(compTyp, name)))
+				}
+			}
+		}
+	}
+	if components.IsEmptyComponents(f.filtered.Components) {
+		f.filtered.Components = nil
 	}
-	return oaf.filtered, nil
 }
 
 // filterPaths processes the paths specified in the configuration and filters them
 // according to the allowed methods. It also collects all references used in the
 // filtered paths.
-func (oaf *OpenAPISpecFilter) filterPaths() {
-	for path, methods := range oaf.cfg.Paths {
-		pathItem := oaf.doc.Paths.Find(path)
+func (f *filterer) filterPaths() error {
+	paths, err := f.selectedPaths()
+	if err != nil {
+		return fmt.Errorf("selectedPaths: %w", err)
+	}
+	for path, methods := range paths {
+		pathItem := f.doc.Paths.Find(path)
 		if pathItem == nil {
-			oaf.logger.Warn("path not found in spec", zap.String("path", path))
+			f.logger.Warn("path not found in spec", zap.String("path", path))
 			continue
 		}
 
-		newPathItem := &openapi3.PathItem{}
+		newPathItem := &openapi3.PathItem{
+			Parameters: pathItem.Parameters,
+			Servers:    pathItem.Servers,
+		}
+		f.collector.SetOrigin(path)
+		f.collector.CollectParameters(pathItem.Parameters)
 		for _, method := range methods {
-			op := oaf.getOperation(pathItem, method, path)
+			op := f.getOperation(pathItem, method, path)
 			if op == nil {
-				oaf.logger.Warn("method not exists for specified path",
+				f.logger.Warn("method not exists for specified path",
 					zap.String("method", method),
 					zap.String("path", path))
 				continue
 			}
-			if !oaf.setOperation(newPathItem, method, path, op) {
+			if !f.setOperation(newPathItem, method, path, op) {
 				continue
 			}
-			oaf.collector.CollectOperation(op)
+			f.collector.SetOrigin(OperationSelector(method, path))
+			f.collector.CollectOperation(op)
 		}
 
-		oaf.filtered.Paths.Set(path, newPathItem)
+		f.filtered.Paths.Set(path, newPathItem)
+	}
+	return nil
+}
+
+// selectedPaths returns the paths and methods to keep: the paths of the
+// configuration along with operations selected by tags, operation IDs or
+// the expression.
+func (f *filterer) selectedPaths() (map[string][]string, error) {
+	sel := f.cfg.Select
+	if sel == nil || (len(sel.Tags) == 0 && len(sel.OperationIDs) == 0 && sel.Expression == "") {
+		return f.cfg.Paths, nil
+	}
+	var expression *expr.Expr
+	if sel.Expression != "" {
+		var err error
+		expression, err = expr.Compile(sel.Expression, expressionVars)
+		if err != nil {
+			return nil, fmt.Errorf("select.expression: %w", err)
+		}
+	}
+
+	paths := make(map[string][]string, len(f.cfg.Paths))
+	for path, methods := range f.cfg.Paths {
+		paths[path] = slices.Clone(methods)
+	}
+	for _, path := range sortedKeys(f.doc.Paths.Map()) {
+		ops := f.doc.Paths.Value(path).Operations()
+		for _, method := range sortedKeys(ops) {
+			op := ops[method]
+			selected := (op.OperationID != "" && slices.Contains(sel.OperationIDs, op.OperationID)) ||
+				slices.ContainsFunc(op.Tags, func(tag string) bool { return slices.Contains(sel.Tags, tag) })
+			if !selected && expression != nil {
+				matched, err := expression.Eval(operationVars(method, path, op))
+				if err != nil {
+					return nil, fmt.Errorf("select.expression: %s: %w", OperationSelector(method, path), err)
+				}
+				selected = matched
+			}
+			if !selected {
+				continue
+			}
+			if !slices.ContainsFunc(paths[path], func(m string) bool { return strings.EqualFold(m, method) }) {
+				paths[path] = append(paths[path], method)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// expressionVars lists the variables of select expressions.
+var expressionVars = []string{"method", "path", "tags", "operationId", "extensions", "deprecated"}
+
+// operationVars returns the values of [expressionVars] for the operation.
+func operationVars(method, path string, op *openapi3.Operation) map[string]any {
+	return map[string]any{
+		"method":      strings.ToUpper(method),
+		"path":        path,
+		"tags":        op.Tags,
+		"operationId": op.OperationID,
+		"extensions":  op.Extensions,
+		"deprecated":  op.Deprecated,
 	}
 }
 
 // getOperation safely retrieves an operation from [openapi3.PathItem] for the specified
 // method. It handles unknown HTTP methods gracefully and returns nil if the
 // method is invalid.
-func (oaf *OpenAPISpecFilter) getOperation(
+func (f *filterer) getOperation(
 	p *openapi3.PathItem,
 	method, path string,
 ) (op *openapi3.Operation) {
 	defer func() {
 		if r := recover(); r != nil {
-			oaf.logger.Warn("unknown HTTP method in filter config",
+			f.logger.Warn("unknown HTTP method in filter config",
 				zap.String("method", method),
 				zap.String("path", path))
 			op = nil
@@ -110,14 +310,14 @@ func (oaf *OpenAPISpecFilter) getOperation(
 // setOperation safely sets an operation in [openapi3.PathItem] for the specified method.
 // It handles unknown HTTP methods gracefully and returns false if the method
 // is invalid.
-func (oaf *OpenAPISpecFilter) setOperation(
+func (f *filterer) setOperation(
 	p *openapi3.PathItem,
 	method, path string,
 	operation *openapi3.Operation,
 ) (ok bool) {
 	defer func() {
 		if r := recover(); r != nil {
-			oaf.logger.Warn("unknown HTTP method in spec",
+			f.logger.Warn("unknown HTTP method in spec",
 				zap.String("method", method),
 				zap.String("path", path))
 			ok = false
@@ -129,40 +329,40 @@ func (oaf *OpenAPISpecFilter) setOperation(
 
 // filterRefs processes all collected references and ensures they are properly
 // included in the filtered spec.
-func (oaf *OpenAPISpecFilter) filterRefs() {
-	for ref := range oaf.collector.Refs() {
-		oaf.filterRef(ref)
+func (f *filterer) filterRefs() {
+	for ref := range f.collector.Refs() {
+		f.filterRef(ref)
 	}
 }
 
 // filterRef processes a single reference and copies the referenced component
 // to the filtered spec.
-func (oaf *OpenAPISpecFilter) filterRef(ref string) {
-	if oaf.doc.Components == nil {
+func (f *filterer) filterRef(ref string) {
+	if f.doc.Components == nil {
 		return
 	}
 
 	def, name, ok := refs.ParseRef(ref)
 	if !ok {
-		oaf.logger.Warn("incorrect ref", zap.String("ref", ref))
+		f.logger.Warn("incorrect ref", zap.String("ref", ref))
 		return
 	}
 
 	compType, ok := components.ComponentDefToType(def)
 	if !ok {
-		oaf.logger.Warn("unknown component definition",
+		f.logger.Warn("unknown component definition",
 			zap.String("def", def),
 			zap.String("name", name),
 			zap.String("ref", ref))
 		return
 	}
 	if !components.ProcessCopyComponent(
-		oaf.doc.Components,
-		oaf.filtered.Components,
+		f.doc.Components,
+		f.filtered.Components,
 		compType,
 		name,
 	) {
-		oaf.logger.Warn("component not found",
+		f.logger.Warn("component not found",
 			zap.String("def", def),
 			zap.String("name", name),
 			zap.String("ref", ref))
@@ -171,42 +371,40 @@ func (oaf *OpenAPISpecFilter) filterRef(ref string) {
 
 // filterComponents processes all components specified in the configuration and
 // copies them to the filtered spec.
-func (oaf *OpenAPISpecFilter) filterComponents() {
-	if oaf.cfg.Components == nil || oaf.doc.Components == nil {
+func (f *filterer) filterComponents() {
+	if f.cfg.Components == nil || f.doc.Components == nil {
 		return
 	}
 
 	for _, compTyp := range components.ComponentTypes() {
-		for _, name := range components.ComponentTypeToCfgNames(oaf.cfg.Components, compTyp) {
+		for _, name := range components.ComponentTypeToCfgNames(f.cfg.Components, compTyp) {
 			if !components.ProcessCopyComponent(
-				oaf.doc.Components,
-				oaf.filtered.Components,
+				f.doc.Components,
+				f.filtered.Components,
 				compTyp,
 				name,
 			) {
-				oaf.logger.Warn("component not found",
+				f.logger.Warn("component not found",
 					zap.String("def", components.ComponentTypeToDef(compTyp)),
 					zap.String("name", name))
 				continue
 			}
-			oaf.collector.CollectComponent(oaf.doc.Components, compTyp, name)
+			f.collector.SetOrigin(refs.ComponentRef(compTyp, name))
+			f.collector.CollectComponent(f.doc.Components, compTyp, name)
 		}
 	}
 }
 
 // filterOther processes additional OpenAPI elements specified in the configuration,
-// including servers, security requirements, tags, and external documentation.
-func (oaf *OpenAPISpecFilter) filterOther() {
-	if oaf.cfg.Servers {
-		oaf.filtered.Servers = oaf.doc.Servers
-	}
-	if oaf.cfg.Security {
-		oaf.filtered.Security = oaf.doc.Security
+// including security requirements, tags, and external documentation.
+func (f *filterer) filterOther() {
+	if f.cfg.Security {
+		f.filtered.Security = f.doc.Security
 	}
-	if oaf.cfg.Tags {
-		oaf.filtered.Tags = oaf.doc.Tags
+	if f.cfg.Tags {
+		f.filtered.Tags = f.doc.Tags
 	}
-	if oaf.cfg.ExternalDocs {
-		oaf.filtered.ExternalDocs = oaf.doc.ExternalDocs
+	if f.cfg.ExternalDocs {
+		f.filtered.ExternalDocs = f.doc.ExternalDocs
 	}
 }
@@ -0,0 +1,87 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// infoField is a field of the info overridden by a template.
+type infoField struct {
+	name  string
+	value string
+	set   func(value string)
+}
+
+// overrideInfo overrides fields of the spec info from the configuration.
+// Field values are templates executed with the original info as data, e.g.
+// "{{ .Title }} (Partner)". The spec is a copy of the source, so its info is
+// modified in place.
+func (f *filterer) overrideInfo(doc *openapi3.T) error {
+	cfg := f.cfg.Info
+	if cfg == nil {
+		return nil
+	}
+	if doc.Info == nil {
+		doc.Info = &openapi3.Info{}
+	}
+	info := doc.Info
+
+	fields := []infoField{
+		{"title", cfg.Title, func(v string) { info.Title = v }},
+		{"version", cfg.Version, func(v string) { info.Version = v }},
+		{"description", cfg.Description, func(v string) { info.Description = v }},
+		{"termsOfService", cfg.TermsOfService, func(v string) { info.TermsOfService = v }},
+	}
+	if c := cfg.Contact; c != nil {
+		fields = append(fields,
+			infoField{"contact.name", c.Name, func(v string) { info.Contact.Name = v }},
+			infoField{"contact.url", c.URL, func(v string) { info.Contact.URL = v }},
+			infoField{"contact.email", c.Email, func(v string) { info.Contact.Email = v }},
+		)
+	}
+	if l := cfg.License; l != nil {
+		fields = append(fields,
+			infoField{"license.name", l.Name, func(v string) { info.License.Name = v }},
+			infoField{"license.url", l.URL, func(v string) { info.License.URL = v }},
+		)
+	}
+
+	// Execute all templates before setting any field, so that every template
+	// sees the original info.
+	values := make(map[int]string, len(fields))
+	for i, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		value, err := executeTemplate(field.name, field.value, info)
+		if err != nil {
+			return fmt.Errorf("info.%s: %w", field.name, err)
+		}
+		values[i] = value
+	}
+	if cfg.Contact != nil && info.Contact == nil {
+		info.Contact = &openapi3.Contact{}
+	}
+	if cfg.License != nil && info.License == nil {
+		info.License = &openapi3.License{}
+	}
+	for i, value := range values {
+		fields[i].set(value)
+	}
+	return nil
+}
+
+func executeTemplate(name, text string, data any) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("template.Parse: %w", err)
+	}
+	var s strings.Builder
+	if err := tmpl.Execute(&s, data); err != nil {
+		return "", fmt.Errorf("tmpl.Execute: %w", err)
+	}
+	return s.String(), nil
+}
@@ -0,0 +1,89 @@
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+func TestOverrideInfo(t *testing.T) {
+	const spec = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.2"
+  description: About pets
+  contact: {name: Pets team, email: pets@example.com}
+paths: {}
+`
+	tests := []struct {
+		name    string
+		cfg     config.InfoConfig
+		want    openapi3.Info
+		wantErr bool
+	}{
+		{
+			name: "templates see the original info",
+			cfg: config.InfoConfig{
+				Title:       "{{ .Title }} (Partner)",
+				Description: "{{ .Title }} {{ .Version }}: {{ .Description }}",
+				Contact:     &config.ContactConfig{Email: "partners+{{ .Contact.Email }}"},
+				License:     &config.LicenseConfig{Name: "{{ .Title }} license"},
+			},
+			want: openapi3.Info{
+				Title:       "Pets (Partner)",
+				Version:     "1.2",
+				Description: "Pets 1.2: About pets",
+				Contact:     &openapi3.Contact{Name: "Pets team", Email: "partners+pets@example.com"},
+				License:     &openapi3.License{Name: "Pets license"},
+			},
+		},
+		{
+			name:    "missing field",
+			cfg:     config.InfoConfig{Title: "{{ .Name }}"},
+			wantErr: true,
+		},
+		{
+			name:    "missing license",
+			cfg:     config.InfoConfig{Title: "{{ .License.Name }}"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := loadTestSpec(t, spec)
+			source, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			result, err := Apply(context.Background(), doc, &config.FilterConfig{PassThrough: true, Info: &tt.cfg})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Apply() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			got, err := json.Marshal(result.Spec.Info)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			want, err := json.Marshal(tt.want)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("info = %s, want %s", got, want)
+			}
+			if after, _ := json.Marshal(doc); string(after) != string(source) {
+				t.Errorf("source changed: %s, want %s", after, source)
+			}
+		})
+	}
+}
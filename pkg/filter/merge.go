@@ -0,0 +1,335 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/components"
+	"github.com/zguydev/openapi-filter/internal/deepcopy"
+	"github.com/zguydev/openapi-filter/internal/refs"
+)
+
+// ConflictPolicy defines how conflicting paths and components of merged
+// specs are resolved. Elements equal in both specs are not conflicts.
+type ConflictPolicy string
+
+const (
+	ConflictError       ConflictPolicy = "error"        // Fail the merge
+	ConflictPreferFirst ConflictPolicy = "prefer-first" // Keep the element of the spec merged first
+	ConflictRename      ConflictPolicy = "rename"       // Rename the element of the spec merged later
+)
+
+var (
+	ErrMergeConflict         = errors.New("merge conflict")
+	ErrUnknownConflictPolicy = errors.New("unknown conflict policy")
+)
+
+// ParseConflictPolicy parses the conflict policy, [ConflictError] if empty.
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(s); policy {
+	case "":
+		return ConflictError, nil
+	case ConflictError, ConflictPreferFirst, ConflictRename:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownConflictPolicy, s)
+	}
+}
+
+// MergeInput is a filtered spec to merge.
+type MergeInput struct {
+	Name string // Name of the spec, used to rename conflicting elements (e.g. "billing")
+	Spec *openapi3.T
+}
+
+// Merge merges filtered specs into a single spec. Paths, components and
+// tags are merged; info, servers, security and external docs are taken
+// from the first spec.
+//
+// With [ConflictRename], conflicting components of a later spec are
+// prefixed with its capitalized name (e.g. "BillingError"), its
+// conflicting paths with its kebab-cased name (e.g. "/billing-api/users"),
+// and its conflicting operationIds with its camel-cased name (e.g.
+// "billingGetUser"). Refs and links to them are rewritten as well.
+// The input specs are not modified.
+func Merge(logger *zap.Logger, inputs []MergeInput, policy ConflictPolicy) (*openapi3.T, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("no specs to merge")
+	}
+	// Conflicting elements of inputs are renamed or dropped, so copy them.
+	inputs = slices.Clone(inputs)
+	for i := range inputs {
+		inputs[i].Spec = deepcopy.Copy(inputs[i].Spec)
+	}
+	first := inputs[0].Spec
+	merged := &openapi3.T{
+		Extensions:   first.Extensions,
+		OpenAPI:      first.OpenAPI,
+		Components:   &openapi3.Components{},
+		Info:         first.Info,
+		Paths:        openapi3.NewPaths(),
+		Security:     first.Security,
+		Servers:      first.Servers,
+		ExternalDocs: first.ExternalDocs,
+	}
+
+	var errs []error
+	for _, input := range inputs {
+		if err := mergeComponents(logger, merged, input, policy); err != nil {
+			errs = append(errs, err)
+		}
+		if err := mergeOperationIDs(logger, merged, input, policy); err != nil {
+			errs = append(errs, err)
+		}
+		if err := mergePaths(logger, merged, input, policy); err != nil {
+			errs = append(errs, err)
+		}
+		merged.Tags = mergeTags(merged.Tags, input.Spec.Tags)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if components.IsEmptyComponents(merged.Components) {
+		merged.Components = nil
+	}
+	return merged, nil
+}
+
+func mergeComponents(logger *zap.Logger, merged *openapi3.T, input MergeInput, policy ConflictPolicy) error {
+	if input.Spec.Components == nil {
+		return nil
+	}
+
+	// Renaming a component changes the components referring to it, which
+	// may make them conflict as well, so rename until there are no conflicts.
+	conflicts := componentConflicts(merged.Components, input.Spec.Components)
+	prefix := namePrefix(input.Name)
+	if policy == ConflictRename && len(conflicts) > 0 && prefix == "" {
+		return fmt.Errorf("%w: components of %q can not be renamed, the spec has no name",
+			ErrMergeConflict, input.Name)
+	}
+	for policy == ConflictRename && len(conflicts) > 0 {
+		names := make(componentNames)
+		for compTyp, compNames := range conflicts {
+			names[compTyp] = make(map[string]string)
+			for _, name := range compNames {
+				names[compTyp][name] = prefix + name
+			}
+		}
+		if _, err := renameComponents(logger, input.Spec, names); err != nil {
+			return fmt.Errorf("%s: renameComponents: %w", input.Name, err)
+		}
+		conflicts = componentConflicts(merged.Components, input.Spec.Components)
+	}
+
+	var errs []error
+	for _, compTyp := range components.ComponentTypes() {
+		for _, name := range conflicts[compTyp] {
+			ref := refs.ComponentRef(compTyp, name)
+			if policy == ConflictError {
+				errs = append(errs, fmt.Errorf("%w: %s of %s", ErrMergeConflict, ref, input.Name))
+				continue
+			}
+			logger.Info("kept first of conflicting components",
+				zap.String("ref", ref), zap.String("input", input.Name))
+		}
+		for _, name := range components.ComponentNames(input.Spec.Components, compTyp) {
+			if components.ComponentValue(merged.Components, compTyp, name) == nil {
+				components.ProcessCopyComponent(input.Spec.Components, merged.Components, compTyp, name)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// componentConflicts returns names of components present in both specs
+// that are not equal.
+func componentConflicts(merged, comps *openapi3.Components) map[components.ComponentType][]string {
+	conflicts := make(map[components.ComponentType][]string)
+	for _, compTyp := range components.ComponentTypes() {
+		for _, name := range components.ComponentNames(comps, compTyp) {
+			mergedValue := components.ComponentValue(merged, compTyp, name)
+			if mergedValue != nil && !jsonEqual(mergedValue, components.ComponentValue(comps, compTyp, name)) {
+				conflicts[compTyp] = append(conflicts[compTyp], name)
+			}
+		}
+	}
+	return conflicts
+}
+
+func mergePaths(logger *zap.Logger, merged *openapi3.T, input MergeInput, policy ConflictPolicy) error {
+	if input.Spec.Paths == nil {
+		return nil
+	}
+
+	var rewriter pathRewriter
+	var errs []error
+	prefix := pathPrefix(input.Name)
+	for _, path := range sortedKeys(input.Spec.Paths.Map()) {
+		pathItem := input.Spec.Paths.Value(path)
+		existing := merged.Paths.Value(path)
+		switch {
+		case existing == nil:
+			merged.Paths.Set(path, pathItem)
+			continue
+		case jsonEqual(existing, pathItem):
+			continue
+		}
+
+		switch policy {
+		case ConflictError:
+			errs = append(errs, fmt.Errorf("%w: path %s of %s", ErrMergeConflict, path, input.Name))
+		case ConflictPreferFirst:
+			logger.Info("kept first of conflicting paths",
+				zap.String("path", path), zap.String("input", input.Name))
+		case ConflictRename:
+			if prefix == "" {
+				errs = append(errs, fmt.Errorf("%w: path %s of %q can not be renamed, the spec has no name",
+					ErrMergeConflict, path, input.Name))
+				continue
+			}
+			newPath := "/" + prefix + path
+			if merged.Paths.Value(newPath) != nil {
+				errs = append(errs, fmt.Errorf("%w: path %s of %s is renamed to existing %s",
+					ErrMergeConflict, path, input.Name, newPath))
+				continue
+			}
+			logger.Info("renamed conflicting path",
+				zap.String("from", path), zap.String("to", newPath), zap.String("input", input.Name))
+			rewriter = append(rewriter, pathRewriteRule{
+				regex:   regexp.MustCompile("^" + regexp.QuoteMeta(path) + "$"),
+				replace: strings.ReplaceAll(newPath, "$", "$$"),
+			})
+			merged.Paths.Set(newPath, pathItem)
+		}
+	}
+	if len(rewriter) > 0 {
+		errs = append(errs, rewriter.rewriteReferences(input.Spec)...)
+	}
+	return errors.Join(errs...)
+}
+
+// mergeOperationIDs resolves operations of the input using operationIds
+// of operations merged already. Operations of paths equal to merged ones
+// are not merged, so they do not conflict.
+func mergeOperationIDs(logger *zap.Logger, merged *openapi3.T, input MergeInput, policy ConflictPolicy) error {
+	if input.Spec.Paths == nil {
+		return nil
+	}
+	mergedIDs := make(map[string]string) // operationId -> operation selector
+	for _, path := range sortedKeys(merged.Paths.Map()) {
+		for method, op := range merged.Paths.Value(path).Operations() {
+			if op.OperationID != "" {
+				mergedIDs[op.OperationID] = OperationSelector(method, path)
+			}
+		}
+	}
+
+	prefix := namePrefix(input.Name)
+	if prefix != "" {
+		prefix = strings.ToLower(prefix[:1]) + prefix[1:]
+	}
+	ids := make(map[string]string)
+	var errs []error
+	for _, path := range sortedKeys(input.Spec.Paths.Map()) {
+		pathItem := input.Spec.Paths.Value(path)
+		if existing := merged.Paths.Value(path); existing != nil && jsonEqual(existing, pathItem) {
+			continue
+		}
+		ops := pathItem.Operations()
+		for _, method := range sortedKeys(ops) {
+			id := ops[method].OperationID
+			other, ok := mergedIDs[id]
+			if id == "" || !ok {
+				continue
+			}
+			switch policy {
+			case ConflictError:
+				errs = append(errs, fmt.Errorf("%w: operationId %q of %s in %s is used by %s",
+					ErrMergeConflict, id, OperationSelector(method, path), input.Name, other))
+			case ConflictPreferFirst:
+				logger.Info("dropped operation with conflicting operationId",
+					zap.String("operationId", id),
+					zap.String("operation", OperationSelector(method, path)),
+					zap.String("input", input.Name))
+				pathItem.SetOperation(method, nil)
+				if len(pathItem.Operations()) == 0 {
+					input.Spec.Paths.Delete(path)
+				}
+			case ConflictRename:
+				if prefix == "" {
+					errs = append(errs, fmt.Errorf("%w: operationId %q of %q can not be renamed, the spec has no name",
+						ErrMergeConflict, id, input.Name))
+					continue
+				}
+				newID := prefix + strings.ToUpper(id[:1]) + id[1:]
+				if _, ok := mergedIDs[newID]; ok {
+					errs = append(errs, fmt.Errorf("%w: operationId %q of %s is renamed to existing %q",
+						ErrMergeConflict, id, input.Name, newID))
+					continue
+				}
+				ids[id] = newID
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if err := renameOperationIDs(logger, input.Spec, ids); err != nil {
+		return fmt.Errorf("%s: renameOperationIDs: %w", input.Name, err)
+	}
+	return nil
+}
+
+// mergeTags adds tags not defined yet, by name.
+func mergeTags(tags, newTags openapi3.Tags) openapi3.Tags {
+	for _, tag := range newTags {
+		if tags.Get(tag.Name) == nil {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// namePrefix converts the input name to a component name prefix, e.g.
+// "billing-api" -> "BillingApi".
+func namePrefix(name string) string {
+	var s strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		s.WriteRune(r)
+	}
+	return s.String()
+}
+
+// pathPrefix converts the input name to a path segment, e.g.
+// "Billing API" -> "billing-api".
+func pathPrefix(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.ToLower(strings.Join(words, "-"))
+}
+
+func jsonEqual(a, b any) bool {
+	aData, errA := json.Marshal(a)
+	bData, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(aData, bData)
+}
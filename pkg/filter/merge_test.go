@@ -0,0 +1,163 @@
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+)
+
+const mergeFirstSpec = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses: {"200": {description: OK}}
+  /pets/{id}:
+    get:
+      operationId: getPetById
+      parameters: [{name: id, in: path, required: true, schema: {type: string}}]
+      responses: {"200": {description: OK}}
+`
+
+const mergeSecondSpec = `
+openapi: 3.0.3
+info: {title: Pets copy, version: "1"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      description: Changed, so the path conflicts
+      responses: {"200": {description: OK}}
+  /animals/{id}:
+    get:
+      operationId: getPetById
+      parameters: [{name: id, in: path, required: true, schema: {type: string}}]
+      responses:
+        "200":
+          description: OK
+          links:
+            self: {operationId: getPetById}
+`
+
+func TestMergeOperationIDConflicts(t *testing.T) {
+	tests := []struct {
+		policy  ConflictPolicy
+		wantErr bool
+		wantIDs []string // operationIds of the merged spec, sorted
+	}{
+		{policy: ConflictError, wantErr: true},
+		{policy: ConflictPreferFirst, wantIDs: []string{"getPetById", "listPets"}},
+		{policy: ConflictRename, wantIDs: []string{"copyGetPetById", "copyListPets", "getPetById", "listPets"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			inputs := []MergeInput{
+				{Name: "pets", Spec: loadTestSpec(t, mergeFirstSpec)},
+				{Name: "copy", Spec: loadTestSpec(t, mergeSecondSpec)},
+			}
+			merged, err := Merge(zap.NewNop(), inputs, tt.policy)
+			if tt.wantErr {
+				if !errors.Is(err, ErrMergeConflict) {
+					t.Fatalf("Merge() error = %v, want %v", err, ErrMergeConflict)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+
+			var ids []string
+			for _, pathItem := range merged.Paths.Map() {
+				for _, op := range pathItem.Operations() {
+					ids = append(ids, op.OperationID)
+				}
+			}
+			slices.Sort(ids)
+			if !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("operationIds = %v, want %v", ids, tt.wantIDs)
+			}
+
+			if tt.policy == ConflictRename {
+				link := merged.Paths.Value("/animals/{id}").Get.Responses.Status(200).Value.Links["self"].Value
+				if link.OperationID != "copyGetPetById" {
+					t.Errorf("link operationId = %q, want %q", link.OperationID, "copyGetPetById")
+				}
+			}
+		})
+	}
+}
+
+func TestMergeDoesNotModifyInputs(t *testing.T) {
+	for _, policy := range []ConflictPolicy{ConflictPreferFirst, ConflictRename} {
+		t.Run(string(policy), func(t *testing.T) {
+			inputs := []MergeInput{
+				{Name: "pets", Spec: loadTestSpec(t, mergeFirstSpec)},
+				{Name: "copy", Spec: loadTestSpec(t, mergeSecondSpec)},
+			}
+			var want [][]byte
+			for _, input := range inputs {
+				data, err := json.Marshal(input.Spec)
+				if err != nil {
+					t.Fatalf("json.Marshal() error = %v", err)
+				}
+				want = append(want, data)
+			}
+			if _, err := Merge(zap.NewNop(), inputs, policy); err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			for i, input := range inputs {
+				if got, _ := json.Marshal(input.Spec); string(got) != string(want[i]) {
+					t.Errorf("input %s changed: %s, want %s", input.Name, got, want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMergeRenamedPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantPath string
+		wantErr  bool
+	}{
+		{name: "copy", wantPath: "/copy/pets"},
+		{name: "Billing API", wantPath: "/billing-api/pets"},
+		{name: " ./ ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputs := []MergeInput{
+				{Name: "pets", Spec: loadTestSpec(t, mergeFirstSpec)},
+				{Name: tt.name, Spec: loadTestSpec(t, mergeSecondSpec)},
+			}
+			merged, err := Merge(zap.NewNop(), inputs, ConflictRename)
+			if tt.wantErr {
+				if !errors.Is(err, ErrMergeConflict) {
+					t.Fatalf("Merge() error = %v, want %v", err, ErrMergeConflict)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Merge() error = %v", err)
+			}
+			if merged.Paths.Value(tt.wantPath) == nil {
+				t.Errorf("paths = %v, want %s", sortedKeys(merged.Paths.Map()), tt.wantPath)
+			}
+		})
+	}
+}
+
+func loadTestSpec(t *testing.T, data string) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(data))
+	if err != nil {
+		t.Fatalf("LoadFromData() error = %v", err)
+	}
+	return doc
+}
@@ -0,0 +1,270 @@
+package filter
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/zguydev/openapi-filter/internal/deepcopy"
+	"github.com/zguydev/openapi-filter/internal/jsonpath"
+)
+
+var ErrInvalidOverlay = errors.New("invalid overlay")
+
+// Overlay is an OpenAPI Overlay 1.0 document: actions changing the nodes
+// of a spec selected by JSONPath queries.
+type Overlay struct {
+	Overlay string          `yaml:"overlay"` // Overlay spec version, e.g. "1.0.0"
+	Info    OverlayInfo     `yaml:"info"`
+	Extends string          `yaml:"extends,omitempty"` // URL of the spec the overlay is meant for, informational
+	Actions []OverlayAction `yaml:"actions"`
+}
+
+// OverlayInfo is the metadata of an overlay.
+type OverlayInfo struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+// OverlayAction changes the nodes selected by its target. Objects are
+// merged with the update recursively, with other values of the update
+// replacing existing ones, and the update is appended to arrays.
+type OverlayAction struct {
+	Target      string `yaml:"target"`                // JSONPath query, e.g. "$.paths['/pets'].get"
+	Description string `yaml:"description,omitempty"` // Description of the action
+	Update      any    `yaml:"update,omitempty"`      // Value to merge into the nodes
+	Remove      bool   `yaml:"remove,omitempty"`      // Remove the nodes
+}
+
+// ParseOverlay parses a YAML or JSON overlay document.
+func ParseOverlay(data []byte) (*Overlay, error) {
+	var overlay Overlay
+	if err := yaml.Unmarshal(data, &overlay); err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal: %w", err)
+	}
+	if !strings.HasPrefix(overlay.Overlay, "1.") {
+		return nil, fmt.Errorf("%w: unsupported version %q", ErrInvalidOverlay, overlay.Overlay)
+	}
+	if len(overlay.Actions) == 0 {
+		return nil, fmt.Errorf("%w: no actions", ErrInvalidOverlay)
+	}
+	for i, action := range overlay.Actions {
+		if _, err := jsonpath.Compile(action.Target); err != nil {
+			return nil, fmt.Errorf("%w: actions[%d].target: %w", ErrInvalidOverlay, i, err)
+		}
+	}
+	return &overlay, nil
+}
+
+// LoadOverlay reads and parses an overlay file.
+func LoadOverlay(path string) (*Overlay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+	overlay, err := ParseOverlay(data)
+	if err != nil {
+		return nil, fmt.Errorf("ParseOverlay: %w", err)
+	}
+	return overlay, nil
+}
+
+// ApplyOverlay applies the actions of the overlay to a copy of the spec
+// in order and returns the changed spec. The changed spec is validated
+// with [Validate], and violations not present in the spec before make
+// the overlay fail.
+func ApplyOverlay(ctx context.Context, logger *zap.Logger, doc *openapi3.T, overlay *Overlay) (*openapi3.T, error) {
+	root, err := specValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	for i, action := range overlay.Actions {
+		path, err := jsonpath.Compile(action.Target)
+		if err != nil {
+			return nil, fmt.Errorf("actions[%d].target: %w", i, err)
+		}
+		nodes := path.Select(root)
+		if len(nodes) == 0 {
+			logger.Warn("overlay action target matches nothing",
+				zap.Int("action", i), zap.String("target", action.Target))
+			continue
+		}
+		switch {
+		case action.Remove:
+			if root, err = removeNodes(root, nodes); err != nil {
+				return nil, fmt.Errorf("actions[%d]: %w", i, err)
+			}
+		case action.Update != nil:
+			for _, n := range nodes {
+				root = setValue(root, n.Location, updateValue(n.Value, deepcopy.Copy(action.Update)))
+			}
+		}
+	}
+
+	data, err := json.Marshal(root)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	result, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("loader.LoadFromData: %w", err)
+	}
+
+	existing := make(map[string]bool)
+	for _, violation := range Validate(ctx, doc) {
+		existing[violation.Error()] = true
+	}
+	var violations []error
+	for _, violation := range Validate(ctx, result) {
+		if !existing[violation.Error()] {
+			violations = append(violations, violation)
+		}
+	}
+	if len(violations) > 0 {
+		return nil, fmt.Errorf("overlay makes the spec invalid: %w", errors.Join(violations...))
+	}
+	return result, nil
+}
+
+// applyOverlays applies the overlay files of the configuration in order.
+func (f *filterer) applyOverlays(ctx context.Context, doc *openapi3.T) error {
+	for _, path := range f.cfg.Overlays {
+		overlay, err := LoadOverlay(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		result, err := ApplyOverlay(ctx, f.logger, doc, overlay)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		*doc = *result
+		f.logger.Info("applied overlay",
+			zap.String("path", path), zap.String("title", overlay.Info.Title))
+	}
+	return nil
+}
+
+// specValue converts the spec to generic JSON values. Numbers are kept
+// as [json.Number], so that large integers are not rounded.
+func specValue(doc *openapi3.T) (any, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decoder.Decode: %w", err)
+	}
+	return v, nil
+}
+
+// updateValue merges the update into the value: objects recursively,
+// arrays by appending the update, other values by replacing them.
+func updateValue(value, update any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		updateObject, ok := update.(map[string]any)
+		if !ok {
+			return update
+		}
+		for key, v := range updateObject {
+			if existing, ok := value[key].(map[string]any); ok {
+				value[key] = updateValue(existing, v)
+				continue
+			}
+			value[key] = v
+		}
+		return value
+	case []any:
+		return append(value, update)
+	default:
+		return update
+	}
+}
+
+// removeNodes removes the nodes from root. Nodes are removed from the
+// last one in document order, so that removing array elements does not
+// shift the indices of the elements still to remove.
+func removeNodes(root any, nodes []jsonpath.Node) (any, error) {
+	locations := make([][]any, 0, len(nodes))
+	for _, n := range nodes {
+		if len(n.Location) == 0 {
+			return nil, errors.New("the root can not be removed")
+		}
+		locations = append(locations, n.Location)
+	}
+	slices.SortFunc(locations, func(a, b []any) int { return -compareLocations(a, b) })
+	locations = slices.CompactFunc(locations, func(a, b []any) bool { return compareLocations(a, b) == 0 })
+
+	for _, location := range locations {
+		parentLocation := location[:len(location)-1]
+		switch parent := valueAt(root, parentLocation).(type) {
+		case map[string]any:
+			delete(parent, location[len(location)-1].(string))
+		case []any:
+			i := location[len(location)-1].(int)
+			root = setValue(root, parentLocation, slices.Delete(slices.Clone(parent), i, i+1))
+		}
+	}
+	return root, nil
+}
+
+func valueAt(root any, location []any) any {
+	v := root
+	for _, key := range location {
+		switch parent := v.(type) {
+		case map[string]any:
+			v = parent[key.(string)]
+		case []any:
+			v = parent[key.(int)]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// setValue sets the value at the location and returns the root, which
+// is the value itself for an empty location.
+func setValue(root any, location []any, value any) any {
+	if len(location) == 0 {
+		return value
+	}
+	switch parent := valueAt(root, location[:len(location)-1]).(type) {
+	case map[string]any:
+		parent[location[len(location)-1].(string)] = value
+	case []any:
+		parent[location[len(location)-1].(int)] = value
+	}
+	return root
+}
+
+// compareLocations orders locations in document order, with the location
+// of a node before the locations of its descendants.
+func compareLocations(a, b []any) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		var c int
+		switch aKey := a[i].(type) {
+		case int:
+			c = cmp.Compare(aKey, b[i].(int))
+		case string:
+			c = cmp.Compare(aKey, b[i].(string))
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
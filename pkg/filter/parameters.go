@@ -0,0 +1,94 @@
+package filter
+
+import (
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/components"
+	"github.com/zguydev/openapi-filter/internal/refs"
+	"github.com/zguydev/openapi-filter/internal/walk"
+)
+
+var (
+	operationType = reflect.TypeFor[openapi3.Operation]()
+	pathItemType  = reflect.TypeFor[openapi3.PathItem]()
+)
+
+// filterParameters drops parameters matching the configured rules from
+// operations, path items and components. Refs to dropped parameter
+// components are dropped along with them.
+func (f *filterer) filterParameters(doc *openapi3.T) {
+	cfg := f.cfg.Parameters
+	if cfg == nil || len(cfg.Drop) == 0 {
+		return
+	}
+
+	walk.Walk(doc, func(pointer string, v reflect.Value) {
+		var params *openapi3.Parameters
+		switch v.Type() {
+		case operationType:
+			params = &v.Addr().Interface().(*openapi3.Operation).Parameters
+		case pathItemType:
+			params = &v.Addr().Interface().(*openapi3.PathItem).Parameters
+		default:
+			return
+		}
+		*params = slices.DeleteFunc(*params, func(paramr *openapi3.ParameterRef) bool {
+			if !f.isParameterDropped(paramr.Value) {
+				return false
+			}
+			f.logger.Debug("dropped parameter",
+				zap.String("pointer", pointer),
+				zap.String("in", paramr.Value.In),
+				zap.String("name", paramr.Value.Name))
+			return true
+		})
+		if len(*params) == 0 {
+			*params = nil
+		}
+	})
+
+	if doc.Components == nil {
+		return
+	}
+	for name, paramr := range doc.Components.Parameters {
+		if f.isParameterDropped(paramr.Value) {
+			delete(doc.Components.Parameters, name)
+			f.logger.Debug("dropped parameter component",
+				zap.String("ref", refs.ComponentRef(components.ComponentTypeParameter, name)))
+		}
+	}
+}
+
+// isParameterDropped reports whether the parameter matches any drop rule.
+// A rule matches if all of its set fields match. Names are matched as
+// glob patterns, case-insensitively for headers.
+func (f *filterer) isParameterDropped(param *openapi3.Parameter) bool {
+	if param == nil {
+		return false
+	}
+	for _, rule := range f.cfg.Parameters.Drop {
+		if rule.In == "" && rule.Name == "" {
+			continue
+		}
+		if rule.In != "" && !strings.EqualFold(rule.In, param.In) {
+			continue
+		}
+		if rule.Name != "" {
+			pattern, name := rule.Name, param.Name
+			if param.In == openapi3.ParameterInHeader {
+				pattern, name = strings.ToLower(pattern), strings.ToLower(name)
+			}
+			if ok, _ := path.Match(pattern, name); !ok {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
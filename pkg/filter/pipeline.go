@@ -0,0 +1,233 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/deepcopy"
+)
+
+// Transformer is a stage of the filtering pipeline. Stages before the
+// [StageSelect] stage transform a copy of the source spec, and stages
+// after it transform the filtered spec.
+type Transformer interface {
+	Transform(ctx context.Context, doc *openapi3.T) error
+}
+
+// TransformerFunc is a function implementing [Transformer].
+type TransformerFunc func(ctx context.Context, doc *openapi3.T) error
+
+// Transform calls fn(ctx, doc).
+func (fn TransformerFunc) Transform(ctx context.Context, doc *openapi3.T) error {
+	return fn(ctx, doc)
+}
+
+// Names of built-in stages. The properties, responses and parameters
+// stages run before select by default, so components referenced only by
+// dropped elements are not kept. Run after select, they leave such
+// components in the filtered spec.
+const (
+	StageProperties = "properties" // Drop schema properties (properties)
+	StageResponses  = "responses"  // Filter responses and media types (statusCodes, contentTypes)
+	StageParameters = "parameters" // Drop parameters (parameters)
+	StageSelect     = "select"     // Select paths, components and top-level elements with the refs they use
+	StageRewrite    = "rewrite"    // Rewrite path keys (rewrite)
+	StageRename     = "rename"     // Rename components and operationIds (rename)
+	StageInfo       = "info"       // Override the info section (info)
+	StageStrip      = "strip"      // Remove kinds of fields (strip)
+	StageOverlay    = "overlay"    // Apply overlay files (overlays)
+)
+
+var ErrUnknownStage = errors.New("unknown stage")
+
+// DefaultStages returns the names of built-in stages in the default order.
+func DefaultStages() []string {
+	return []string{
+		StageProperties,
+		StageResponses,
+		StageParameters,
+		StageSelect,
+		StageRewrite,
+		StageRename,
+		StageInfo,
+		StageStrip,
+		StageOverlay,
+	}
+}
+
+// customStage is a stage registered by [WithStageBefore] or [WithStageAfter].
+type customStage struct {
+	name        string
+	anchor      string
+	after       bool
+	transformer Transformer
+}
+
+// WithStageBefore adds a named stage running before the anchor stage,
+// e.g. before [StageSelect] to transform the spec before selection.
+func WithStageBefore(anchor, name string, transformer Transformer) Option {
+	return func(o *options) {
+		o.stages = append(o.stages, customStage{name: name, anchor: anchor, transformer: transformer})
+	}
+}
+
+// WithStageAfter adds a named stage running after the anchor stage,
+// e.g. after [StageSelect] to transform the filtered spec.
+func WithStageAfter(anchor, name string, transformer Transformer) Option {
+	return func(o *options) {
+		o.stages = append(o.stages, customStage{name: name, anchor: anchor, after: true, transformer: transformer})
+	}
+}
+
+type stage struct {
+	name        string
+	transformer Transformer
+}
+
+// stages returns the stages to run: built-in and custom stages in the
+// order and with the stages disabled in the configuration.
+func (f *filterer) stages(custom []customStage) ([]stage, error) {
+	transformers := map[string]Transformer{
+		StageProperties: TransformerFunc(func(_ context.Context, doc *openapi3.T) error {
+			f.filterProperties(doc)
+			return nil
+		}),
+		StageResponses: TransformerFunc(func(_ context.Context, doc *openapi3.T) error {
+			f.filterResponses(doc)
+			return nil
+		}),
+		StageParameters: TransformerFunc(func(_ context.Context, doc *openapi3.T) error {
+			f.filterParameters(doc)
+			return nil
+		}),
+		StageSelect: TransformerFunc(func(context.Context, *openapi3.T) error {
+			return f.selectSpec()
+		}),
+		StageRewrite: TransformerFunc(func(_ context.Context, doc *openapi3.T) error {
+			return f.rewritePaths(doc)
+		}),
+		StageRename: TransformerFunc(func(_ context.Context, doc *openapi3.T) error {
+			return f.rename(doc)
+		}),
+		StageInfo: TransformerFunc(func(_ context.Context, doc *openapi3.T) error {
+			return f.overrideInfo(doc)
+		}),
+		StageStrip: TransformerFunc(func(_ context.Context, doc *openapi3.T) error {
+			f.stripFields(doc)
+			return nil
+		}),
+		StageOverlay: TransformerFunc(f.applyOverlays),
+	}
+
+	for _, c := range custom {
+		if _, ok := transformers[c.name]; ok || c.name == "" {
+			return nil, fmt.Errorf("invalid stage name %q, it is empty or already used", c.name)
+		}
+		transformers[c.name] = c.transformer
+	}
+
+	order := DefaultStages()
+	cfg := f.cfg.Pipeline
+	if cfg != nil && len(cfg.Stages) > 0 {
+		order = slices.Clone(cfg.Stages)
+		for i, name := range order {
+			if _, ok := transformers[name]; !ok {
+				return nil, fmt.Errorf("pipeline.stages: %w: %q", ErrUnknownStage, name)
+			}
+			if slices.Contains(order[:i], name) {
+				return nil, fmt.Errorf("pipeline.stages: stage %q is listed more than once", name)
+			}
+		}
+	}
+
+	// Custom stages not listed in pipeline.stages stay next to their anchor.
+	for _, c := range custom {
+		if slices.Contains(order, c.name) {
+			continue
+		}
+		i := slices.Index(order, c.anchor)
+		if i == -1 {
+			return nil, fmt.Errorf("%w: %q to add stage %q next to", ErrUnknownStage, c.anchor, c.name)
+		}
+		if c.after {
+			i++
+		}
+		order = slices.Insert(order, i, c.name)
+	}
+
+	selectAt := slices.Index(order, StageSelect)
+	if selectAt == -1 {
+		return nil, fmt.Errorf("pipeline.stages: stage %q is required", StageSelect)
+	}
+	// Config paths and component names are the source ones, so they
+	// would no longer match after a rewrite or rename.
+	for _, name := range []string{StageRewrite, StageRename} {
+		if slices.Contains(order[:selectAt], name) {
+			return nil, fmt.Errorf("pipeline.stages: stage %q must run after %q", name, StageSelect)
+		}
+	}
+
+	if cfg != nil {
+		for _, name := range cfg.Disable {
+			if _, ok := transformers[name]; !ok {
+				return nil, fmt.Errorf("pipeline.disable: %w: %q", ErrUnknownStage, name)
+			}
+			if name == StageSelect {
+				return nil, fmt.Errorf("pipeline.disable: stage %q can not be disabled", StageSelect)
+			}
+			order = slices.DeleteFunc(order, func(s string) bool { return s == name })
+		}
+	}
+
+	stages := make([]stage, 0, len(order))
+	for _, name := range order {
+		stages = append(stages, stage{name: name, transformer: transformers[name]})
+	}
+	return stages, nil
+}
+
+// run runs the stages, checking ctx for cancellation between them.
+func (f *filterer) run(ctx context.Context, stages []stage) error {
+	selected := false
+	for _, s := range stages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		doc := f.filtered
+		if !selected {
+			if f.doc == f.source && f.modifiesSource(s.name) {
+				f.doc = deepcopy.Copy(f.source)
+			}
+			doc = f.doc
+		}
+		f.logger.Debug("running stage", zap.String("stage", s.name))
+		if err := s.transformer.Transform(ctx, doc); err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+		selected = selected || s.name == StageSelect
+	}
+	return ctx.Err()
+}
+
+// modifiesSource reports whether the stage, run before select, may modify
+// the spec. Built-in stages with nothing to do leave it as is, so the
+// source spec does not need to be copied for them.
+func (f *filterer) modifiesSource(name string) bool {
+	switch name {
+	case StageSelect:
+		return false
+	case StageProperties:
+		return f.cfg.Properties != nil
+	case StageResponses:
+		return f.cfg.StatusCodes != nil || f.cfg.ContentTypes != nil
+	case StageParameters:
+		return f.cfg.Parameters != nil && len(f.cfg.Parameters.Drop) > 0
+	default:
+		return true
+	}
+}
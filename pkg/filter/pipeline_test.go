@@ -0,0 +1,168 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+func TestStages(t *testing.T) {
+	noop := TransformerFunc(func(context.Context, *openapi3.T) error { return nil })
+	tests := []struct {
+		name     string
+		pipeline *config.PipelineConfig
+		opts     []Option
+		want     []string
+		wantErr  string
+	}{
+		{
+			name: "default",
+			want: DefaultStages(),
+		},
+		{
+			name:     "reorder",
+			pipeline: &config.PipelineConfig{Stages: []string{StageResponses, StageSelect, StageProperties, StageRename, StageRewrite}},
+			want:     []string{StageResponses, StageSelect, StageProperties, StageRename, StageRewrite},
+		},
+		{
+			name:     "disable",
+			pipeline: &config.PipelineConfig{Disable: []string{StageStrip, StageProperties}},
+			want:     []string{StageResponses, StageParameters, StageSelect, StageRewrite, StageRename, StageInfo, StageOverlay},
+		},
+		{
+			name:     "unknown stage",
+			pipeline: &config.PipelineConfig{Stages: []string{StageSelect, "sort"}},
+			wantErr:  `pipeline.stages: unknown stage: "sort"`,
+		},
+		{
+			name:     "unknown disabled stage",
+			pipeline: &config.PipelineConfig{Disable: []string{"sort"}},
+			wantErr:  `pipeline.disable: unknown stage: "sort"`,
+		},
+		{
+			name:     "duplicate stage",
+			pipeline: &config.PipelineConfig{Stages: []string{StageStrip, StageSelect, StageStrip}},
+			wantErr:  `stage "strip" is listed more than once`,
+		},
+		{
+			name:     "missing select",
+			pipeline: &config.PipelineConfig{Stages: []string{StageStrip}},
+			wantErr:  `stage "select" is required`,
+		},
+		{
+			name:     "disabled select",
+			pipeline: &config.PipelineConfig{Disable: []string{StageSelect}},
+			wantErr:  `stage "select" can not be disabled`,
+		},
+		{
+			name:     "rewrite before select",
+			pipeline: &config.PipelineConfig{Stages: []string{StageRewrite, StageSelect}},
+			wantErr:  `stage "rewrite" must run after "select"`,
+		},
+		{
+			name: "custom stages",
+			opts: []Option{
+				WithStageBefore(StageSelect, "before", noop),
+				WithStageAfter(StageOverlay, "after", noop),
+				WithStageAfter("before", "chained", noop),
+			},
+			want: []string{StageProperties, StageResponses, StageParameters, "before", "chained", StageSelect,
+				StageRewrite, StageRename, StageInfo, StageStrip, StageOverlay, "after"},
+		},
+		{
+			name:     "custom stages anchored in reordered stages",
+			pipeline: &config.PipelineConfig{Stages: []string{StageSelect, StageStrip, StageInfo}},
+			opts: []Option{
+				WithStageBefore(StageSelect, "before", noop),
+				WithStageAfter(StageStrip, "after", noop),
+			},
+			want: []string{"before", StageSelect, StageStrip, "after", StageInfo},
+		},
+		{
+			name:     "custom stage listed",
+			pipeline: &config.PipelineConfig{Stages: []string{StageSelect, "custom", StageStrip}},
+			opts:     []Option{WithStageAfter(StageStrip, "custom", noop)},
+			want:     []string{StageSelect, "custom", StageStrip},
+		},
+		{
+			name:     "custom stage disabled",
+			pipeline: &config.PipelineConfig{Disable: []string{"custom"}},
+			opts:     []Option{WithStageAfter(StageStrip, "custom", noop)},
+			want:     DefaultStages(),
+		},
+		{
+			name:     "custom stage anchored to an unlisted stage",
+			pipeline: &config.PipelineConfig{Stages: []string{StageSelect}},
+			opts:     []Option{WithStageAfter(StageStrip, "custom", noop)},
+			wantErr:  `unknown stage: "strip" to add stage "custom" next to`,
+		},
+		{
+			name:    "custom stage named like a built-in one",
+			opts:    []Option{WithStageAfter(StageStrip, StageInfo, noop)},
+			wantErr: `invalid stage name "info"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var o options
+			for _, opt := range tt.opts {
+				opt(&o)
+			}
+			f := &filterer{cfg: &config.FilterConfig{Pipeline: tt.pipeline}}
+			stages, err := f.stages(o.stages)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("stages() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("stages() error = %v", err)
+			}
+			var names []string
+			for _, s := range stages {
+				names = append(names, s.name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("stages() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyCustomStages(t *testing.T) {
+	var got []string
+	record := func(name string) Transformer {
+		return TransformerFunc(func(_ context.Context, doc *openapi3.T) error {
+			got = append(got, name+" "+doc.Info.Title)
+			return nil
+		})
+	}
+	cfg := &config.FilterConfig{
+		Paths:    map[string][]string{"/pets": {"get"}},
+		Info:     &config.InfoConfig{Title: "Filtered"},
+		Pipeline: &config.PipelineConfig{Stages: []string{StageSelect, StageInfo}},
+	}
+	_, err := Apply(context.Background(), loadTestSpec(t, mergeFirstSpec), cfg,
+		WithStageBefore(StageSelect, "before", record("before")),
+		WithStageAfter(StageInfo, "after", record("after")))
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	want := []string{"before Pets", "after Filtered"}
+	if !slices.Equal(got, want) {
+		t.Errorf("stages ran as %v, want %v", got, want)
+	}
+
+	_, err = Apply(context.Background(), loadTestSpec(t, mergeFirstSpec), cfg,
+		WithStageAfter(StageStrip, "custom", record("custom")))
+	if !errors.Is(err, ErrUnknownStage) {
+		t.Errorf("Apply() error = %v, want %v", err, ErrUnknownStage)
+	}
+}
@@ -0,0 +1,97 @@
+package filter
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/walk"
+)
+
+var schemaType = reflect.TypeFor[openapi3.Schema]()
+
+// filterProperties drops schema properties configured by name within
+// component schemas or marked with configured extensions, keeping
+// required properties lists consistent.
+func (f *filterer) filterProperties(doc *openapi3.T) {
+	cfg := f.cfg.Properties
+	if cfg == nil {
+		return
+	}
+
+	for name, props := range cfg.Drop {
+		var schema *openapi3.Schema
+		if doc.Components != nil && doc.Components.Schemas[name] != nil {
+			schema = doc.Components.Schemas[name].Value
+		}
+		if schema == nil {
+			f.logger.Warn("schema for properties filter not found", zap.String("schema", name))
+			continue
+		}
+		for _, prop := range props {
+			if _, ok := schema.Properties[prop]; !ok {
+				f.logger.Warn("property not found in schema",
+					zap.String("schema", name),
+					zap.String("property", prop))
+				continue
+			}
+			dropProperty(schema, prop)
+			f.logger.Debug("dropped property",
+				zap.String("schema", name),
+				zap.String("property", prop))
+		}
+	}
+
+	if len(cfg.DropExtensions) == 0 {
+		return
+	}
+	walk.Walk(doc, func(pointer string, v reflect.Value) {
+		if v.Type() != schemaType {
+			return
+		}
+		schema := v.Addr().Interface().(*openapi3.Schema)
+		for _, prop := range sortedKeys(schema.Properties) {
+			if hasAnyExtension(schema.Properties[prop], cfg.DropExtensions) {
+				dropProperty(schema, prop)
+				f.logger.Debug("dropped property",
+					zap.String("schema", pointer),
+					zap.String("property", prop))
+			}
+		}
+	})
+}
+
+func dropProperty(schema *openapi3.Schema, prop string) {
+	delete(schema.Properties, prop)
+	schema.Required = slices.DeleteFunc(schema.Required, func(name string) bool {
+		return name == prop
+	})
+	if len(schema.Required) == 0 {
+		schema.Required = nil
+	}
+}
+
+// hasAnyExtension reports whether the schema has any of the extensions
+// set to a value other than false or null.
+func hasAnyExtension(scr *openapi3.SchemaRef, extensions []string) bool {
+	if scr == nil {
+		return false
+	}
+	for _, exts := range []map[string]any{scr.Extensions, valueExtensions(scr)} {
+		for _, ext := range extensions {
+			if value, ok := exts[ext]; ok && value != nil && value != false {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func valueExtensions(scr *openapi3.SchemaRef) map[string]any {
+	if scr.Value == nil {
+		return nil
+	}
+	return scr.Value.Extensions
+}
@@ -0,0 +1,288 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/components"
+	"github.com/zguydev/openapi-filter/internal/refs"
+	"github.com/zguydev/openapi-filter/internal/walk"
+)
+
+var ErrNameCollision = errors.New("name collision")
+
+// componentNames maps component names to new names by component type.
+type componentNames map[components.ComponentType]map[string]string
+
+// rename renames components and operationIds of the filtered spec as
+// specified in the configuration.
+func (f *filterer) rename(doc *openapi3.T) error {
+	cfg := f.cfg.Rename
+	if cfg == nil {
+		return nil
+	}
+	names := make(componentNames)
+	for def, compNames := range cfg.Components {
+		compTyp, ok := components.ComponentDefToType(def)
+		if !ok {
+			return fmt.Errorf("rename.components: unknown component definition %q", def)
+		}
+		names[compTyp] = compNames
+	}
+	if err := f.templateNames(doc, names); err != nil {
+		return fmt.Errorf("templateNames: %w", err)
+	}
+	renamed, err := renameComponents(f.logger, doc, names)
+	if err != nil {
+		return fmt.Errorf("renameComponents: %w", err)
+	}
+	f.renamed = renamed
+	if err := renameOperationIDs(f.logger, doc, cfg.OperationIDs); err != nil {
+		return fmt.Errorf("renameOperationIDs: %w", err)
+	}
+	return nil
+}
+
+// templateNames adds new names of filtered components that are not renamed
+// explicitly, made by the name template of their type or the prefix.
+func (f *filterer) templateNames(doc *openapi3.T, names componentNames) error {
+	cfg := f.cfg.Rename
+	if (cfg.Prefix == "" && len(cfg.Templates) == 0) || doc.Components == nil {
+		return nil
+	}
+	for def := range cfg.Templates {
+		if _, ok := components.ComponentDefToType(def); !ok {
+			return fmt.Errorf("rename.templates: unknown component definition %q", def)
+		}
+	}
+	for _, compTyp := range components.ComponentTypes() {
+		def := components.ComponentTypeToDef(compTyp)
+		tmpl, ok := cfg.Templates[def]
+		if !ok && cfg.Prefix == "" {
+			continue
+		}
+		for _, name := range components.ComponentNames(doc.Components, compTyp) {
+			if _, ok := names[compTyp][name]; ok {
+				continue
+			}
+			newName := cfg.Prefix + name
+			if tmpl != "" {
+				var err error
+				newName, err = executeTemplate(def, tmpl, struct{ Name, Type string }{name, def})
+				if err != nil {
+					return fmt.Errorf("rename.templates.%s: %w", def, err)
+				}
+			}
+			if names[compTyp] == nil {
+				names[compTyp] = make(map[string]string)
+			}
+			names[compTyp][name] = newName
+		}
+	}
+	return nil
+}
+
+// renameComponents renames components of the spec, rewrites all refs to
+// them and returns the names of renamed components. Returns
+// [ErrNameCollision] if several components of the same type would get the
+// same name.
+func renameComponents(logger *zap.Logger, doc *openapi3.T, names componentNames) (componentNames, error) {
+	comps := doc.Components
+	if comps == nil || len(names) == 0 {
+		return nil, nil
+	}
+
+	renamedComps := &openapi3.Components{Extensions: comps.Extensions}
+	renamed := make(componentNames)
+	var errs []error
+	for _, compTyp := range components.ComponentTypes() {
+		compNames := components.ComponentNames(comps, compTyp)
+		for name := range names[compTyp] {
+			if !slices.Contains(compNames, name) {
+				logger.Warn("component to rename not found",
+					zap.String("ref", refs.ComponentRef(compTyp, name)))
+			}
+		}
+
+		taken := make(map[string]string) // New name -> name
+		for _, name := range compNames {
+			newName := name
+			if n := names[compTyp][name]; n != "" {
+				newName = n
+			}
+			if other, ok := taken[newName]; ok {
+				errs = append(errs, fmt.Errorf("%w: %s and %s are both named %q", ErrNameCollision,
+					refs.ComponentRef(compTyp, other), refs.ComponentRef(compTyp, name), newName))
+				continue
+			}
+			taken[newName] = name
+			components.ProcessCopyComponentAs(comps, renamedComps, compTyp, name, newName)
+			if newName != name {
+				if renamed[compTyp] == nil {
+					renamed[compTyp] = make(map[string]string)
+				}
+				renamed[compTyp][name] = newName
+				logger.Info("renamed component",
+					zap.String("from", refs.ComponentRef(compTyp, name)),
+					zap.String("to", refs.ComponentRef(compTyp, newName)))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	doc.Components = renamedComps
+	rewriteRefs(doc, renamed)
+	return renamed, nil
+}
+
+// rewriteRefs rewrites refs to renamed components across the spec,
+// including discriminator mappings and security requirements.
+func rewriteRefs(doc *openapi3.T, renamed componentNames) {
+	if len(renamed) == 0 {
+		return
+	}
+	newRefs := make(map[string]string)
+	for compTyp, compNames := range renamed {
+		for name, newName := range compNames {
+			newRefs[refs.ComponentRef(compTyp, name)] = refs.ComponentRef(compTyp, newName)
+		}
+	}
+
+	doc.Security = renameSecurityRequirements(doc.Security, renamed)
+	visited := make(map[uintptr]struct{}) // Structs may be shared, rewrite them once
+	walk.Walk(doc, func(_ string, v reflect.Value) {
+		if _, ok := visited[v.Addr().Pointer()]; ok {
+			return
+		}
+		visited[v.Addr().Pointer()] = struct{}{}
+
+		if walk.IsRefWrapper(v) {
+			ref := v.FieldByName("Ref")
+			if newRef, ok := newRefs[ref.String()]; ok {
+				ref.SetString(newRef)
+			}
+			return
+		}
+		switch x := v.Addr().Interface().(type) {
+		case *openapi3.Discriminator:
+			x.Mapping = renameMapping(x.Mapping, newRefs, renamed[components.ComponentTypeSchema])
+		case *openapi3.Operation:
+			if x.Security != nil {
+				security := renameSecurityRequirements(*x.Security, renamed)
+				x.Security = &security
+			}
+		}
+	})
+}
+
+// renameMapping rewrites discriminator mapping values, which are either
+// schema refs or schema names.
+func renameMapping(mapping openapi3.StringMap, newRefs, schemaNames map[string]string) openapi3.StringMap {
+	if mapping == nil {
+		return nil
+	}
+	renamed := make(openapi3.StringMap, len(mapping))
+	for key, value := range mapping {
+		if newRef, ok := newRefs[value]; ok {
+			value = newRef
+		} else if newName, ok := schemaNames[value]; ok && !strings.Contains(value, "/") {
+			value = newName
+		}
+		renamed[key] = value
+	}
+	return renamed
+}
+
+func renameSecurityRequirements(reqs openapi3.SecurityRequirements, renamed componentNames) openapi3.SecurityRequirements {
+	schemeNames := renamed[components.ContentTypeSecuritySchema]
+	if reqs == nil || len(schemeNames) == 0 {
+		return reqs
+	}
+	renamedReqs := make(openapi3.SecurityRequirements, 0, len(reqs))
+	for _, req := range reqs {
+		renamedReq := make(openapi3.SecurityRequirement, len(req))
+		for name, scopes := range req {
+			if newName, ok := schemeNames[name]; ok {
+				name = newName
+			}
+			renamedReq[name] = scopes
+		}
+		renamedReqs = append(renamedReqs, renamedReq)
+	}
+	return renamedReqs
+}
+
+// renameOperationIDs renames operationIds of the spec and
+// rewrites links referring to them. Returns [ErrNameCollision] if several
+// operations would get the same operationId.
+func renameOperationIDs(logger *zap.Logger, doc *openapi3.T, ids map[string]string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var ops []*openapi3.Operation
+	var links []*openapi3.Link
+	visited := make(map[uintptr]struct{})
+	walk.Walk(doc, func(_ string, v reflect.Value) {
+		if _, ok := visited[v.Addr().Pointer()]; ok {
+			return
+		}
+		visited[v.Addr().Pointer()] = struct{}{}
+		switch x := v.Addr().Interface().(type) {
+		case *openapi3.Operation:
+			ops = append(ops, x)
+		case *openapi3.Link:
+			links = append(links, x)
+		}
+	})
+
+	found := make(map[string]struct{})
+	taken := make(map[string]string) // New operationId -> operationId
+	var errs []error
+	for _, op := range ops {
+		if op.OperationID == "" {
+			continue
+		}
+		found[op.OperationID] = struct{}{}
+		newID := op.OperationID
+		if id := ids[op.OperationID]; id != "" {
+			newID = id
+		}
+		if other, ok := taken[newID]; ok && (other != newID || op.OperationID != newID) {
+			errs = append(errs, fmt.Errorf("%w: operationIds %q and %q are both renamed to %q",
+				ErrNameCollision, other, op.OperationID, newID))
+			continue
+		}
+		taken[newID] = op.OperationID
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	for id := range ids {
+		if _, ok := found[id]; !ok {
+			logger.Warn("operationId to rename not found", zap.String("operationId", id))
+		}
+	}
+
+	for _, op := range ops {
+		if newID := ids[op.OperationID]; newID != "" {
+			logger.Info("renamed operationId",
+				zap.String("from", op.OperationID),
+				zap.String("to", newID))
+			op.OperationID = newID
+		}
+	}
+	for _, link := range links {
+		if newID := ids[link.OperationID]; newID != "" {
+			link.OperationID = newID
+		}
+	}
+	return nil
+}
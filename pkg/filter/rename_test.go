@@ -0,0 +1,315 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/components"
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+const renameSpec = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+security: [{apiKey: []}, {oauth: [read]}]
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      security: [{apiKey: [], oauth: [read]}]
+      responses:
+        "200": {$ref: "#/components/responses/Pets"}
+        default:
+          description: Error
+          links:
+            pet: {operationId: getPet}
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters: [{$ref: "#/components/parameters/Id"}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+components:
+  schemas:
+    Pet:
+      type: object
+      discriminator:
+        propertyName: kind
+        mapping:
+          cat: "#/components/schemas/Cat"
+          dog: Dog
+          bird: Bird
+          fish: "other.yaml#/components/schemas/Cat"
+      properties:
+        kind: {type: string}
+    Cat:
+      allOf: [{$ref: "#/components/schemas/Pet"}]
+    Dog:
+      allOf: [{$ref: "#/components/schemas/Pet"}]
+    Bird:
+      allOf: [{$ref: "#/components/schemas/Pet"}]
+  responses:
+    Pets:
+      description: OK
+      content:
+        application/json:
+          schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+  parameters:
+    Id: {name: id, in: path, required: true, schema: {type: string}}
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+    oauth:
+      type: oauth2
+      flows: {clientCredentials: {tokenUrl: https://example.com/token, scopes: {read: Read}}}
+`
+
+func TestRenameComponents(t *testing.T) {
+	doc := loadTestSpec(t, renameSpec)
+	names := componentNames{
+		components.ComponentTypeSchema:       {"Pet": "Animal", "Cat": "Feline", "Dog": "Canine", "Missing": "Other"},
+		components.ContentTypeSecuritySchema: {"apiKey": "ApiKeyAuth"},
+		components.ComponentTypeResponse:     {"Pets": "PetList"},
+	}
+	renamed, err := renameComponents(zap.NewNop(), doc, names)
+	if err != nil {
+		t.Fatalf("renameComponents() error = %v", err)
+	}
+	wantRenamed := componentNames{
+		components.ComponentTypeSchema:       {"Pet": "Animal", "Cat": "Feline", "Dog": "Canine"},
+		components.ContentTypeSecuritySchema: {"apiKey": "ApiKeyAuth"},
+		components.ComponentTypeResponse:     {"Pets": "PetList"},
+	}
+	if !reflect.DeepEqual(renamed, wantRenamed) {
+		t.Errorf("renameComponents() = %v, want %v", renamed, wantRenamed)
+	}
+
+	wantNames := map[components.ComponentType][]string{
+		components.ComponentTypeSchema:       {"Animal", "Bird", "Canine", "Feline"},
+		components.ComponentTypeResponse:     {"PetList"},
+		components.ComponentTypeParameter:    {"Id"},
+		components.ContentTypeSecuritySchema: {"ApiKeyAuth", "oauth"},
+	}
+	for compTyp, want := range wantNames {
+		got := components.ComponentNames(doc.Components, compTyp)
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s names = %v, want %v", components.ComponentTypeToDef(compTyp), got, want)
+		}
+	}
+
+	// Refs
+	list := doc.Paths.Value("/pets").Get
+	if got, want := list.Responses.Status(200).Ref, "#/components/responses/PetList"; got != want {
+		t.Errorf("response ref = %q, want %q", got, want)
+	}
+	get := doc.Paths.Value("/pets/{id}").Get
+	if got, want := get.Parameters[0].Ref, "#/components/parameters/Id"; got != want {
+		t.Errorf("parameter ref = %q, want %q", got, want)
+	}
+	if got, want := get.Responses.Status(200).Value.Content.Get("application/json").Schema.Ref, "#/components/schemas/Animal"; got != want {
+		t.Errorf("schema ref = %q, want %q", got, want)
+	}
+	items := doc.Components.Responses["PetList"].Value.Content.Get("application/json").Schema.Value.Items
+	if got, want := items.Ref, "#/components/schemas/Animal"; got != want {
+		t.Errorf("items ref = %q, want %q", got, want)
+	}
+	for _, name := range []string{"Feline", "Canine", "Bird"} {
+		if got, want := doc.Components.Schemas[name].Value.AllOf[0].Ref, "#/components/schemas/Animal"; got != want {
+			t.Errorf("%s allOf ref = %q, want %q", name, got, want)
+		}
+	}
+
+	// Discriminator mappings
+	wantMapping := openapi3.StringMap{
+		"cat":  "#/components/schemas/Feline",
+		"dog":  "Canine",
+		"bird": "Bird",
+		"fish": "other.yaml#/components/schemas/Cat",
+	}
+	if got := doc.Components.Schemas["Animal"].Value.Discriminator.Mapping; !reflect.DeepEqual(got, wantMapping) {
+		t.Errorf("discriminator mapping = %v, want %v", got, wantMapping)
+	}
+
+	// Security requirements
+	wantSecurity := openapi3.SecurityRequirements{{"ApiKeyAuth": {}}, {"oauth": {"read"}}}
+	if !reflect.DeepEqual(doc.Security, wantSecurity) {
+		t.Errorf("security = %v, want %v", doc.Security, wantSecurity)
+	}
+	wantOpSecurity := openapi3.SecurityRequirements{{"ApiKeyAuth": {}, "oauth": {"read"}}}
+	if list.Security == nil || !reflect.DeepEqual(*list.Security, wantOpSecurity) {
+		t.Errorf("operation security = %v, want %v", list.Security, wantOpSecurity)
+	}
+	if get.Security != nil {
+		t.Errorf("operation security = %v, want nil", *get.Security)
+	}
+}
+
+func TestRenameComponentsCollision(t *testing.T) {
+	tests := []struct {
+		name  string
+		names componentNames
+	}{
+		{name: "existing name", names: componentNames{components.ComponentTypeSchema: {"Cat": "Dog"}}},
+		{name: "same new name", names: componentNames{components.ComponentTypeSchema: {"Cat": "Animal", "Dog": "Animal"}}},
+		{name: "security schemes", names: componentNames{components.ContentTypeSecuritySchema: {"apiKey": "oauth"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := loadTestSpec(t, renameSpec)
+			before := doc.Components
+			if _, err := renameComponents(zap.NewNop(), doc, tt.names); !errors.Is(err, ErrNameCollision) {
+				t.Fatalf("renameComponents() error = %v, want %v", err, ErrNameCollision)
+			}
+			if doc.Components != before {
+				t.Error("components changed on collision")
+			}
+		})
+	}
+
+	// Swapping names is not a collision.
+	doc := loadTestSpec(t, renameSpec)
+	names := componentNames{components.ComponentTypeSchema: {"Cat": "Dog", "Dog": "Cat"}}
+	if _, err := renameComponents(zap.NewNop(), doc, names); err != nil {
+		t.Fatalf("renameComponents() error = %v", err)
+	}
+	if got, want := doc.Components.Schemas["Pet"].Value.Discriminator.Mapping["cat"], "#/components/schemas/Dog"; got != want {
+		t.Errorf("discriminator mapping cat = %q, want %q", got, want)
+	}
+}
+
+func TestTemplateNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.RenameConfig
+		want    componentNames
+		wantErr bool
+	}{
+		{
+			name: "prefix",
+			cfg:  config.RenameConfig{Prefix: "Zoo", Components: map[string]map[string]string{"schemas": {"Pet": "Animal"}}},
+			want: componentNames{
+				components.ComponentTypeSchema:       {"Pet": "Animal", "Cat": "ZooCat", "Dog": "ZooDog", "Bird": "ZooBird"},
+				components.ComponentTypeParameter:    {"Id": "ZooId"},
+				components.ComponentTypeResponse:     {"Pets": "ZooPets"},
+				components.ContentTypeSecuritySchema: {"apiKey": "ZooapiKey", "oauth": "Zoooauth"},
+			},
+		},
+		{
+			name: "templates",
+			cfg: config.RenameConfig{Templates: map[string]string{
+				"schemas":   "{{ .Name }}V2",
+				"responses": "{{ .Name }}{{ .Type }}",
+			}},
+			want: componentNames{
+				components.ComponentTypeSchema:   {"Pet": "PetV2", "Cat": "CatV2", "Dog": "DogV2", "Bird": "BirdV2"},
+				components.ComponentTypeResponse: {"Pets": "Petsresponses"},
+			},
+		},
+		{
+			name: "templates and prefix",
+			cfg:  config.RenameConfig{Prefix: "Zoo", Templates: map[string]string{"schemas": "{{ .Name }}V2", "securitySchemes": "{{ .Name }}"}},
+			want: componentNames{
+				components.ComponentTypeSchema:       {"Pet": "PetV2", "Cat": "CatV2", "Dog": "DogV2", "Bird": "BirdV2"},
+				components.ComponentTypeParameter:    {"Id": "ZooId"},
+				components.ComponentTypeResponse:     {"Pets": "ZooPets"},
+				components.ContentTypeSecuritySchema: {"apiKey": "apiKey", "oauth": "oauth"},
+			},
+		},
+		{
+			name:    "unknown definition",
+			cfg:     config.RenameConfig{Templates: map[string]string{"models": "{{ .Name }}V2"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid template",
+			cfg:     config.RenameConfig{Templates: map[string]string{"schemas": "{{ .Name"}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			cfg:     config.RenameConfig{Templates: map[string]string{"schemas": "{{ .Version }}"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &filterer{cfg: &config.FilterConfig{Rename: &tt.cfg}}
+			names := make(componentNames)
+			for def, compNames := range tt.cfg.Components {
+				compTyp, _ := components.ComponentDefToType(def)
+				names[compTyp] = compNames
+			}
+			err := f.templateNames(loadTestSpec(t, renameSpec), names)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("templateNames() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("templateNames() error = %v", err)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("templateNames() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenameOperationIDs(t *testing.T) {
+	doc := loadTestSpec(t, renameSpec)
+	ids := map[string]string{"getPet": "getAnimal", "listPets": "listAnimals", "missing": "other"}
+	if err := renameOperationIDs(zap.NewNop(), doc, ids); err != nil {
+		t.Fatalf("renameOperationIDs() error = %v", err)
+	}
+	list := doc.Paths.Value("/pets").Get
+	if got, want := list.OperationID, "listAnimals"; got != want {
+		t.Errorf("operationId = %q, want %q", got, want)
+	}
+	if got, want := doc.Paths.Value("/pets/{id}").Get.OperationID, "getAnimal"; got != want {
+		t.Errorf("operationId = %q, want %q", got, want)
+	}
+	if got, want := list.Responses.Default().Value.Links["pet"].Value.OperationID, "getAnimal"; got != want {
+		t.Errorf("link operationId = %q, want %q", got, want)
+	}
+}
+
+func TestRenameOperationIDsCollision(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     map[string]string
+		wantErr bool
+	}{
+		{name: "existing operationId", ids: map[string]string{"listPets": "getPet"}, wantErr: true},
+		{name: "same new operationId", ids: map[string]string{"listPets": "pets", "getPet": "pets"}, wantErr: true},
+		{name: "swap", ids: map[string]string{"listPets": "getPet", "getPet": "listPets"}},
+		{name: "same operationId", ids: map[string]string{"listPets": "listPets"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := loadTestSpec(t, renameSpec)
+			err := renameOperationIDs(zap.NewNop(), doc, tt.ids)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNameCollision) {
+					t.Fatalf("renameOperationIDs() error = %v, want %v", err, ErrNameCollision)
+				}
+				if got := doc.Paths.Value("/pets").Get.OperationID; got != "listPets" {
+					t.Errorf("operationId = %q, want unchanged on collision", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renameOperationIDs() error = %v", err)
+			}
+		})
+	}
+}
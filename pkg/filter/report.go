@@ -0,0 +1,156 @@
+package filter
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/zguydev/openapi-filter/internal/components"
+	"github.com/zguydev/openapi-filter/internal/refs"
+)
+
+// Report describes the result of filtering: which operations and
+// components are kept, and which are dropped.
+type Report struct {
+	Operations        []OperationReport `json:"operations"`
+	Components        []ComponentReport `json:"components"`
+	DroppedPaths      []string          `json:"droppedPaths"`
+	DroppedOperations []OperationReport `json:"droppedOperations"`
+	DroppedComponents []ComponentReport `json:"droppedComponents"`
+}
+
+// OperationReport identifies an operation in the source spec, and for kept
+// operations its new path and operationId in the filtered spec, if changed.
+type OperationReport struct {
+	Method         string `json:"method"`
+	Path           string `json:"path"`
+	OperationID    string `json:"operationId,omitempty"`
+	NewPath        string `json:"newPath,omitempty"`        // Rewritten path
+	NewOperationID string `json:"newOperationId,omitempty"` // Renamed operationId
+}
+
+// ComponentReport identifies a component in the source spec and the
+// selectors that pulled it into the filtered spec.
+type ComponentReport struct {
+	Ref      string   `json:"ref"`
+	NewRef   string   `json:"newRef,omitempty"`   // Ref of the renamed component in the filtered spec
+	Selected bool     `json:"selected,omitempty"` // Selected in the filter config
+	PulledBy []string `json:"pulledBy,omitempty"` // Selectors it is referenced from
+	Chain    []string `json:"chain,omitempty"`    // Shortest chain from a selector to the component
+}
+
+// OperationSelector returns the selector name of an operation, e.g. "GET /pets".
+func OperationSelector(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+func newReport() *Report {
+	return &Report{
+		Operations:        []OperationReport{},
+		Components:        []ComponentReport{},
+		DroppedPaths:      []string{},
+		DroppedOperations: []OperationReport{},
+		DroppedComponents: []ComponentReport{},
+	}
+}
+
+func (f *filterer) report() *Report {
+	report := newReport()
+	f.reportOperations(report)
+	f.reportComponents(report)
+	return report
+}
+
+func (f *filterer) reportOperations(report *Report) {
+	for _, path := range sortedKeys(f.source.Paths.Map()) {
+		var kept *openapi3.PathItem
+		newPath := f.rewrittenPath(path)
+		if f.filtered.Paths != nil {
+			kept = f.filtered.Paths.Value(newPath)
+		}
+		if kept == nil {
+			report.DroppedPaths = append(report.DroppedPaths, path)
+		}
+
+		ops := f.source.Paths.Value(path).Operations()
+		for _, method := range sortedKeys(ops) {
+			opReport := OperationReport{
+				Method:      method,
+				Path:        path,
+				OperationID: ops[method].OperationID,
+			}
+			var keptOp *openapi3.Operation
+			if kept != nil {
+				keptOp = kept.GetOperation(method)
+			}
+			if keptOp != nil {
+				if newPath != path {
+					opReport.NewPath = newPath
+				}
+				if keptOp.OperationID != opReport.OperationID {
+					opReport.NewOperationID = keptOp.OperationID
+				}
+				report.Operations = append(report.Operations, opReport)
+			} else {
+				report.DroppedOperations = append(report.DroppedOperations, opReport)
+			}
+		}
+	}
+}
+
+func (f *filterer) reportComponents(report *Report) {
+	if f.source.Components == nil {
+		return
+	}
+	selected := make(map[string]struct{})
+	if f.cfg.Components != nil {
+		for _, compTyp := range components.ComponentTypes() {
+			for _, name := range components.ComponentTypeToCfgNames(f.cfg.Components, compTyp) {
+				selected[refs.ComponentRef(compTyp, name)] = struct{}{}
+			}
+		}
+	}
+
+	for _, compTyp := range components.ComponentTypes() {
+		docNames := components.ComponentNames(f.source.Components, compTyp)
+		var keptNames []string
+		if f.filtered.Components != nil {
+			keptNames = components.ComponentNames(f.filtered.Components, compTyp)
+		}
+		for _, name := range docNames {
+			ref := refs.ComponentRef(compTyp, name)
+			keptName := name
+			if newName, ok := f.renamed[compTyp][name]; ok {
+				keptName = newName
+			}
+			if !slices.Contains(keptNames, keptName) {
+				report.DroppedComponents = append(report.DroppedComponents,
+					ComponentReport{Ref: ref})
+				continue
+			}
+			var newRef string
+			if keptName != name {
+				newRef = refs.ComponentRef(compTyp, keptName)
+			}
+			_, isSelected := selected[ref]
+			report.Components = append(report.Components, ComponentReport{
+				Ref:      ref,
+				NewRef:   newRef,
+				Selected: isSelected,
+				PulledBy: f.collector.Origins(ref),
+				Chain:    f.collector.Chain(ref),
+			})
+		}
+	}
+}
+
+func sortedKeys[M ~map[string]V, V any](m M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,108 @@
+package filter
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+const reportSpec = `
+openapi: 3.0.3
+info: {title: Users, version: "1"}
+paths:
+  /internal/v2/users:
+    get:
+      operationId: listUsers
+      responses: {"200": {description: OK}}
+    post:
+      operationId: createUser
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+  /internal/v2/users/{id}:
+    get:
+      operationId: getUser
+      parameters: [{name: id, in: path, required: true, schema: {type: string}}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/User"}
+  /internal/v3/users/{id}:
+    get:
+      operationId: getUserV3
+      parameters: [{name: id, in: path, required: true, schema: {type: string}}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/UserV3"}
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        address: {$ref: "#/components/schemas/Address"}
+    Address: {type: object}
+    Error: {type: object}
+    UserV3: {type: object}
+`
+
+func TestReport(t *testing.T) {
+	cfg := &config.FilterConfig{
+		Paths:      map[string][]string{"/internal/v2/users": {"post"}, "/internal/v2/users/{id}": {"get"}},
+		Components: &config.FilterComponentsConfig{Schemas: []string{"Error"}},
+		Rewrite: &config.RewriteConfig{Paths: []config.PathRewriteConfig{
+			{Prefix: "/internal/v2", Replace: "/api"},
+		}},
+		Rename: &config.RenameConfig{
+			Components:   map[string]map[string]string{"schemas": {"User": "Account"}},
+			OperationIDs: map[string]string{"getUser": "getAccount"},
+		},
+	}
+	result, err := Apply(context.Background(), loadTestSpec(t, reportSpec), cfg)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	got := result.Report
+
+	wantOps := []OperationReport{
+		{Method: "POST", Path: "/internal/v2/users", OperationID: "createUser", NewPath: "/api/users"},
+		{Method: "GET", Path: "/internal/v2/users/{id}", OperationID: "getUser", NewPath: "/api/users/{id}", NewOperationID: "getAccount"},
+	}
+	if !reflect.DeepEqual(got.Operations, wantOps) {
+		t.Errorf("Operations = %+v, want %+v", got.Operations, wantOps)
+	}
+	wantDroppedOps := []OperationReport{
+		{Method: "GET", Path: "/internal/v2/users", OperationID: "listUsers"},
+		{Method: "GET", Path: "/internal/v3/users/{id}", OperationID: "getUserV3"},
+	}
+	if !reflect.DeepEqual(got.DroppedOperations, wantDroppedOps) {
+		t.Errorf("DroppedOperations = %+v, want %+v", got.DroppedOperations, wantDroppedOps)
+	}
+	if want := []string{"/internal/v3/users/{id}"}; !reflect.DeepEqual(got.DroppedPaths, want) {
+		t.Errorf("DroppedPaths = %v, want %v", got.DroppedPaths, want)
+	}
+
+	wantComps := []ComponentReport{
+		{Ref: "#/components/schemas/Address", PulledBy: []string{"GET /internal/v2/users/{id}", "POST /internal/v2/users"},
+			Chain: []string{"GET /internal/v2/users/{id}", "#/components/schemas/User", "#/components/schemas/Address"}},
+		{Ref: "#/components/schemas/Error", Selected: true, PulledBy: []string{}},
+		{Ref: "#/components/schemas/User", NewRef: "#/components/schemas/Account",
+			PulledBy: []string{"GET /internal/v2/users/{id}", "POST /internal/v2/users"},
+			Chain:    []string{"GET /internal/v2/users/{id}", "#/components/schemas/User"}},
+	}
+	if !reflect.DeepEqual(got.Components, wantComps) {
+		t.Errorf("Components = %+v, want %+v", got.Components, wantComps)
+	}
+	if want := []ComponentReport{{Ref: "#/components/schemas/UserV3"}}; !reflect.DeepEqual(got.DroppedComponents, want) {
+		t.Errorf("DroppedComponents = %+v, want %+v", got.DroppedComponents, want)
+	}
+}
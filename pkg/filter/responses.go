@@ -0,0 +1,131 @@
+package filter
+
+import (
+	"mime"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/walk"
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+var (
+	responsesType   = reflect.TypeFor[openapi3.Responses]()
+	responseType    = reflect.TypeFor[openapi3.Response]()
+	requestBodyType = reflect.TypeFor[openapi3.RequestBody]()
+)
+
+// filterResponses drops responses by status code and media types of
+// responses and request bodies by content type. Dropping all responses of
+// an operation is logged as a warning, as the result is an invalid spec.
+func (f *filterer) filterResponses(doc *openapi3.T) {
+	statusCodes, contentTypes := f.cfg.StatusCodes, f.cfg.ContentTypes
+	if statusCodes == nil && contentTypes == nil {
+		return
+	}
+
+	walk.Walk(doc, func(pointer string, v reflect.Value) {
+		switch v.Type() {
+		case responsesType:
+			if statusCodes != nil {
+				f.filterStatusCodes(pointer, v.Addr().Interface().(*openapi3.Responses))
+			}
+		case responseType:
+			if contentTypes != nil {
+				f.filterContent(pointer, v.Addr().Interface().(*openapi3.Response).Content)
+			}
+		case requestBodyType:
+			if contentTypes != nil {
+				f.filterContent(pointer, v.Addr().Interface().(*openapi3.RequestBody).Content)
+			}
+		}
+	})
+}
+
+func (f *filterer) filterStatusCodes(pointer string, responses *openapi3.Responses) {
+	for code := range responses.Map() {
+		if !isStatusCodeIncluded(f.cfg.StatusCodes, code) {
+			responses.Delete(code)
+			f.logger.Debug("dropped response",
+				zap.String("pointer", pointer),
+				zap.String("code", code))
+		}
+	}
+	if responses.Len() == 0 {
+		f.logger.Warn("all responses dropped", zap.String("pointer", pointer))
+	}
+}
+
+func (f *filterer) filterContent(pointer string, content openapi3.Content) {
+	for contentType := range content {
+		if !isIncluded(f.cfg.ContentTypes, contentType, matchContentType) {
+			delete(content, contentType)
+			f.logger.Debug("dropped media type",
+				zap.String("pointer", pointer),
+				zap.String("contentType", contentType))
+		}
+	}
+}
+
+// isIncluded reports whether the value is included by the include and
+// exclude patterns. An empty include list includes everything.
+func isIncluded(cfg *config.IncludeExcludeConfig, value string, match func(pattern, value string) bool) bool {
+	matchesAny := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if match(pattern, value) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(cfg.Include) > 0 && !matchesAny(cfg.Include) {
+		return false
+	}
+	return !matchesAny(cfg.Exclude)
+}
+
+// isStatusCodeIncluded reports whether the response with the status code
+// is kept. A range response (e.g. "2XX") covers the codes of its range, so
+// it is also kept if an included code is in its range (e.g. "200"), unless
+// the range itself is excluded.
+func isStatusCodeIncluded(cfg *config.IncludeExcludeConfig, code string) bool {
+	if isIncluded(cfg, code, matchStatusCode) {
+		return true
+	}
+	code = strings.ToUpper(code)
+	if len(code) != 3 || !strings.HasSuffix(code, "XX") {
+		return false
+	}
+	inRange := func(pattern string) bool {
+		_, err := strconv.Atoi(pattern)
+		return len(pattern) == 3 && err == nil && pattern[0] == code[0]
+	}
+	excluded := func(pattern string) bool { return matchStatusCode(pattern, code) }
+	return slices.ContainsFunc(cfg.Include, inRange) && !slices.ContainsFunc(cfg.Exclude, excluded)
+}
+
+// matchStatusCode matches a status code against a pattern, which is
+// either a status code, a range (e.g. "4XX") or "default".
+func matchStatusCode(pattern, code string) bool {
+	pattern, code = strings.ToUpper(pattern), strings.ToUpper(code)
+	if len(pattern) == 3 && strings.HasSuffix(pattern, "XX") && len(code) == 3 {
+		return pattern[0] == code[0]
+	}
+	return pattern == code
+}
+
+// matchContentType matches a content type against a glob pattern (e.g.
+// "application/*"), ignoring case and media type parameters.
+func matchContentType(pattern, contentType string) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(contentType))
+	return ok
+}
@@ -0,0 +1,33 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+func TestIsStatusCodeIncluded(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.IncludeExcludeConfig
+		code string
+		want bool
+	}{
+		{name: "included code", cfg: config.IncludeExcludeConfig{Include: []string{"200"}}, code: "200", want: true},
+		{name: "other code", cfg: config.IncludeExcludeConfig{Include: []string{"200"}}, code: "201", want: false},
+		{name: "code in included range", cfg: config.IncludeExcludeConfig{Include: []string{"2XX"}}, code: "204", want: true},
+		{name: "range covering included code", cfg: config.IncludeExcludeConfig{Include: []string{"200"}}, code: "2XX", want: true},
+		{name: "lower case range", cfg: config.IncludeExcludeConfig{Include: []string{"200"}}, code: "2xx", want: true},
+		{name: "range not covering included code", cfg: config.IncludeExcludeConfig{Include: []string{"200"}}, code: "4XX", want: false},
+		{name: "excluded range", cfg: config.IncludeExcludeConfig{Include: []string{"200"}, Exclude: []string{"2XX"}}, code: "2XX", want: false},
+		{name: "excluded code does not exclude range", cfg: config.IncludeExcludeConfig{Exclude: []string{"200"}}, code: "2XX", want: true},
+		{name: "default", cfg: config.IncludeExcludeConfig{Include: []string{"200"}}, code: "default", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStatusCodeIncluded(&tt.cfg, tt.code); got != tt.want {
+				t.Errorf("isStatusCodeIncluded(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/walk"
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+var ErrPathCollision = errors.New("path collision")
+
+var pathParamRegexp = regexp.MustCompile(`\{[^}]*\}`)
+
+// pathRewriter rewrites path keys by the configured rules.
+type pathRewriter []pathRewriteRule
+
+type pathRewriteRule struct {
+	prefix  string
+	regex   *regexp.Regexp
+	replace string
+}
+
+func newPathRewriter(cfgs []config.PathRewriteConfig) (pathRewriter, error) {
+	rewriter := make(pathRewriter, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		if (cfg.Prefix == "") == (cfg.Regex == "") {
+			return nil, fmt.Errorf("rewrite.paths[%d]: exactly one of prefix and regex must be set", i)
+		}
+		rule := pathRewriteRule{
+			prefix:  strings.TrimSuffix(cfg.Prefix, "/"),
+			replace: cfg.Replace,
+		}
+		if cfg.Regex != "" {
+			re, err := regexp.Compile(cfg.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("rewrite.paths[%d]: regexp.Compile: %w", i, err)
+			}
+			rule.regex = re
+		}
+		rewriter = append(rewriter, rule)
+	}
+	return rewriter, nil
+}
+
+// rewrite returns the path rewritten by the first matching rule, and
+// whether any rule matched.
+func (r pathRewriter) rewrite(path string) (string, bool) {
+	for _, rule := range r {
+		var rewritten string
+		switch {
+		case rule.regex != nil:
+			if !rule.regex.MatchString(path) {
+				continue
+			}
+			rewritten = rule.regex.ReplaceAllString(path, rule.replace)
+		case path == rule.prefix || strings.HasPrefix(path, rule.prefix+"/"):
+			rewritten = strings.TrimSuffix(rule.replace, "/") + path[len(rule.prefix):]
+		default:
+			continue
+		}
+		if !strings.HasPrefix(rewritten, "/") {
+			rewritten = "/" + rewritten
+		}
+		return rewritten, true
+	}
+	return path, false
+}
+
+// rewriteOperationRef rewrites the path of a local operation ref, e.g.
+// "#/paths/~1internal~1v2~1users/get".
+func (r pathRewriter) rewriteOperationRef(ref string) string {
+	doc, pointer, ok := strings.Cut(ref, "#")
+	if !ok || doc != "" {
+		return ref
+	}
+	rest, ok := strings.CutPrefix(pointer, "/paths/")
+	if !ok {
+		return ref
+	}
+	token, tail, hasTail := strings.Cut(rest, "/")
+	path, ok := r.rewrite(unescapePointer(token))
+	if !ok {
+		return ref
+	}
+	ref = "#/paths/" + walk.EscapePointer(path)
+	if hasTail {
+		ref += "/" + tail
+	}
+	return ref
+}
+
+// rewriteExpression rewrites paths in the literal parts of a callback
+// expression, e.g. "{$request.body#/callbackUrl}/internal/v2/events" or
+// "https://example.com/internal/v2/events". Runtime expressions enclosed
+// in braces are kept as is.
+func (r pathRewriter) rewriteExpression(expr string) string {
+	var s strings.Builder
+	for expr != "" {
+		start := strings.Index(expr, "{$")
+		if start == -1 {
+			s.WriteString(r.rewriteLiteral(expr))
+			break
+		}
+		end := strings.IndexByte(expr[start:], '}')
+		if end == -1 {
+			s.WriteString(r.rewriteLiteral(expr[:start]))
+			s.WriteString(expr[start:])
+			break
+		}
+		end += start + 1
+		s.WriteString(r.rewriteLiteral(expr[:start]))
+		s.WriteString(expr[start:end])
+		expr = expr[end:]
+	}
+	return s.String()
+}
+
+func (r pathRewriter) rewriteLiteral(literal string) string {
+	var base string
+	if _, rest, ok := strings.Cut(literal, "://"); ok {
+		i := strings.IndexByte(rest, '/')
+		if i == -1 {
+			return literal
+		}
+		base, literal = literal[:len(literal)-len(rest)+i], rest[i:]
+	}
+	if !strings.HasPrefix(literal, "/") {
+		return base + literal
+	}
+	path, query, hasQuery := strings.Cut(literal, "?")
+	path, _ = r.rewrite(path)
+	if hasQuery {
+		path += "?" + query
+	}
+	return base + path
+}
+
+// rewritePaths rewrites path keys of the filtered spec by the configured
+// rules, along with link operation refs and callback expressions
+// mentioning them. Returns [ErrPathCollision] if several paths are
+// rewritten to the same (or an equivalent templated) path.
+func (f *filterer) rewritePaths(doc *openapi3.T) error {
+	if f.cfg.Rewrite == nil || len(f.cfg.Rewrite.Paths) == 0 || doc.Paths == nil {
+		return nil
+	}
+	rewriter, err := newPathRewriter(f.cfg.Rewrite.Paths)
+	if err != nil {
+		return fmt.Errorf("newPathRewriter: %w", err)
+	}
+
+	paths := openapi3.NewPaths()
+	paths.Extensions = doc.Paths.Extensions
+	sources := make(map[string]string)
+	var errs []error
+	for _, path := range sortedKeys(doc.Paths.Map()) {
+		newPath, _ := rewriter.rewrite(path)
+		key := pathParamRegexp.ReplaceAllString(newPath, "{}")
+		if other, ok := sources[key]; ok {
+			errs = append(errs, fmt.Errorf("%w: %s and %s are rewritten to %s",
+				ErrPathCollision, other, path, newPath))
+			continue
+		}
+		sources[key] = path
+		if newPath != path {
+			f.logger.Info("rewrote path", zap.String("from", path), zap.String("to", newPath))
+			f.rewrittenPaths[path] = newPath
+		}
+		paths.Set(newPath, doc.Paths.Value(path))
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	doc.Paths = paths
+	return errors.Join(rewriter.rewriteReferences(doc)...)
+}
+
+// rewriteReferences rewrites link operation refs and callback expressions
+// of the spec. Returns [ErrPathCollision] errors for callback expressions
+// rewritten to existing ones.
+func (r pathRewriter) rewriteReferences(doc *openapi3.T) (errs []error) {
+	walk.Walk(doc, func(pointer string, v reflect.Value) {
+		switch x := v.Addr().Interface().(type) {
+		case *openapi3.Link:
+			x.OperationRef = r.rewriteOperationRef(x.OperationRef)
+		case *openapi3.Callback:
+			for expr, pathItem := range x.Map() {
+				if newExpr := r.rewriteExpression(expr); newExpr != expr {
+					if x.Value(newExpr) != nil {
+						errs = append(errs, fmt.Errorf("%w: %s: %s is rewritten to existing %s",
+							ErrPathCollision, pointer, expr, newExpr))
+						continue
+					}
+					x.Delete(expr)
+					x.Set(newExpr, pathItem)
+				}
+			}
+		}
+	})
+	return errs
+}
+
+// rewrittenPath returns the path key of a source path in the filtered spec.
+func (f *filterer) rewrittenPath(path string) string {
+	if newPath, ok := f.rewrittenPaths[path]; ok {
+		return newPath
+	}
+	return path
+}
+
+func unescapePointer(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
@@ -0,0 +1,171 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+func TestPathRewriterRewrite(t *testing.T) {
+	rewriter, err := newPathRewriter([]config.PathRewriteConfig{
+		{Prefix: "/internal/v2/", Replace: "/api"},
+		{Prefix: "/legacy", Replace: "/"},
+		{Regex: `^/v(\d+)/(\w+)`, Replace: "$2/v$1"},
+		{Prefix: "/internal", Replace: "/private"},
+	})
+	if err != nil {
+		t.Fatalf("newPathRewriter() error = %v", err)
+	}
+	tests := []struct {
+		path, want string
+		matched    bool
+	}{
+		{path: "/internal/v2/users", want: "/api/users", matched: true},
+		{path: "/internal/v2", want: "/api", matched: true},
+		{path: "/internal/v2x/users", want: "/private/v2x/users", matched: true},
+		{path: "/legacy/users/{id}", want: "/users/{id}", matched: true},
+		{path: "/legacy", want: "/", matched: true},
+		{path: "/v1/users/{id}", want: "/users/v1/{id}", matched: true},
+		{path: "/internalx", want: "/internalx"},
+		{path: "/users", want: "/users"},
+	}
+	for _, tt := range tests {
+		got, matched := rewriter.rewrite(tt.path)
+		if got != tt.want || matched != tt.matched {
+			t.Errorf("rewrite(%q) = %q, %v, want %q, %v", tt.path, got, matched, tt.want, tt.matched)
+		}
+	}
+}
+
+func TestNewPathRewriterErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.PathRewriteConfig
+	}{
+		{name: "no prefix or regex", cfg: config.PathRewriteConfig{Replace: "/api"}},
+		{name: "prefix and regex", cfg: config.PathRewriteConfig{Prefix: "/a", Regex: "^/a", Replace: "/api"}},
+		{name: "invalid regex", cfg: config.PathRewriteConfig{Regex: "(", Replace: "/api"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newPathRewriter([]config.PathRewriteConfig{tt.cfg}); err == nil {
+				t.Error("newPathRewriter() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestPathRewriterReferences(t *testing.T) {
+	rewriter, err := newPathRewriter([]config.PathRewriteConfig{{Prefix: "/internal/v2", Replace: "/api"}})
+	if err != nil {
+		t.Fatalf("newPathRewriter() error = %v", err)
+	}
+	operationRefs := map[string]string{
+		"#/paths/~1internal~1v2~1users~1{id}/get": "#/paths/~1api~1users~1{id}/get",
+		"#/paths/~1internal~1v2~1users":           "#/paths/~1api~1users",
+		"#/paths/~1users/get":                     "#/paths/~1users/get",
+		"other.yaml#/paths/~1internal~1v2~1users": "other.yaml#/paths/~1internal~1v2~1users",
+		"#/components/links/Self":                 "#/components/links/Self",
+	}
+	for ref, want := range operationRefs {
+		if got := rewriter.rewriteOperationRef(ref); got != want {
+			t.Errorf("rewriteOperationRef(%q) = %q, want %q", ref, got, want)
+		}
+	}
+	expressions := map[string]string{
+		"{$request.body#/callbackUrl}/internal/v2/events":                "{$request.body#/callbackUrl}/api/events",
+		"https://example.com/internal/v2/events?id={$response.body#/id}": "https://example.com/api/events?id={$response.body#/id}",
+		"{$request.query.url}":             "{$request.query.url}",
+		"{$request.body#/internal/v2/url}": "{$request.body#/internal/v2/url}",
+		"https://example.com":              "https://example.com",
+	}
+	for expr, want := range expressions {
+		if got := rewriter.rewriteExpression(expr); got != want {
+			t.Errorf("rewriteExpression(%q) = %q, want %q", expr, got, want)
+		}
+	}
+}
+
+const rewriteSpec = `
+openapi: 3.0.3
+info: {title: Users, version: "1"}
+paths:
+  /internal/v2/users:
+    post:
+      operationId: createUser
+      responses:
+        "201":
+          description: Created
+          links:
+            self:
+              operationRef: "#/paths/~1internal~1v2~1users~1{id}/get"
+      callbacks:
+        created:
+          "{$request.body#/callbackUrl}/internal/v2/events":
+            post:
+              responses: {"200": {description: OK}}
+  /internal/v2/users/{id}:
+    get:
+      operationId: getUser
+      parameters: [{name: id, in: path, required: true, schema: {type: string}}]
+      responses: {"200": {description: OK}}
+  /internal/v3/users/{userId}:
+    get:
+      operationId: getUserV3
+      parameters: [{name: userId, in: path, required: true, schema: {type: string}}]
+      responses: {"200": {description: OK}}
+`
+
+func TestRewritePaths(t *testing.T) {
+	cfg := &config.FilterConfig{
+		PassThrough: true,
+		Rewrite: &config.RewriteConfig{Paths: []config.PathRewriteConfig{
+			{Prefix: "/internal/v2", Replace: "/api"},
+		}},
+	}
+	result, err := Apply(context.Background(), loadTestSpec(t, rewriteSpec), cfg)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	doc := result.Spec
+	for _, path := range []string{"/api/users", "/api/users/{id}", "/internal/v3/users/{userId}"} {
+		if doc.Paths.Value(path) == nil {
+			t.Errorf("paths = %v, want %s", sortedKeys(doc.Paths.Map()), path)
+		}
+	}
+	post := doc.Paths.Value("/api/users").Post
+	link := post.Responses.Status(201).Value.Links["self"].Value
+	if want := "#/paths/~1api~1users~1{id}/get"; link.OperationRef != want {
+		t.Errorf("link operationRef = %q, want %q", link.OperationRef, want)
+	}
+	callback := post.Callbacks["created"].Value
+	if want := "{$request.body#/callbackUrl}/api/events"; callback.Value(want) == nil || callback.Len() != 1 {
+		t.Errorf("callback expressions = %v, want %s", sortedKeys(callback.Map()), want)
+	}
+}
+
+func TestRewritePathsCollision(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []config.PathRewriteConfig
+	}{
+		{name: "same path", rules: []config.PathRewriteConfig{
+			{Prefix: "/internal/v2/users", Replace: "/users"},
+			{Prefix: "/internal/v3/users/{userId}", Replace: "/users"},
+		}},
+		{name: "parameter names", rules: []config.PathRewriteConfig{
+			{Regex: `^/internal/v\d+`, Replace: "/api"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.FilterConfig{PassThrough: true, Rewrite: &config.RewriteConfig{Paths: tt.rules}}
+			_, err := Apply(context.Background(), loadTestSpec(t, rewriteSpec), cfg)
+			if !errors.Is(err, ErrPathCollision) {
+				t.Errorf("Apply() error = %v, want %v", err, ErrPathCollision)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package filter
+
+import (
+	"context"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/zguydev/openapi-filter/internal/components"
+	"github.com/zguydev/openapi-filter/internal/deepcopy"
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+// ComponentType is the type of a component, e.g. [Schema].
+type ComponentType = components.ComponentType
+
+const (
+	Schema         = components.ComponentTypeSchema
+	Parameter      = components.ComponentTypeParameter
+	Header         = components.ComponentTypeHeader
+	RequestBody    = components.ComponentTypeRequestBody
+	Response       = components.ComponentTypeResponse
+	SecurityScheme = components.ContentTypeSecuritySchema
+	Example        = components.ContentTypeExample
+	Link           = components.ContentTypeLink
+	Callback       = components.ContentTypeCallback
+)
+
+// Selection builds a filter config in code, e.g.
+//
+//	cfg := filter.Select().
+//		Path("/pets", "get").
+//		Tag("billing").
+//		Component(filter.Schema, "Pet").
+//		Config()
+//
+// Every method corresponds to a config key, so a selection filters the
+// same way as a config file with the same keys.
+type Selection struct {
+	cfg config.FilterConfig
+}
+
+// Select starts a selection, which keeps nothing until something is selected.
+func Select() *Selection {
+	return &Selection{}
+}
+
+// Path keeps operations of the path with the given methods (paths).
+func (s *Selection) Path(path string, methods ...string) *Selection {
+	if s.cfg.Paths == nil {
+		s.cfg.Paths = make(map[string][]string)
+	}
+	s.cfg.Paths[path] = append(s.cfg.Paths[path], methods...)
+	return s
+}
+
+// Tag keeps operations with any of the tags (select.tags).
+func (s *Selection) Tag(tags ...string) *Selection {
+	s.selectConfig().Tags = append(s.selectConfig().Tags, tags...)
+	return s
+}
+
+// OperationID keeps operations with the operation IDs (select.operationIds).
+func (s *Selection) OperationID(ids ...string) *Selection {
+	s.selectConfig().OperationIDs = append(s.selectConfig().OperationIDs, ids...)
+	return s
+}
+
+// Expression keeps operations matching the expression (select.expression).
+// Expressions of several calls are combined with ||.
+func (s *Selection) Expression(expr string) *Selection {
+	if s.selectConfig().Expression != "" {
+		expr = "(" + s.selectConfig().Expression + ") || (" + expr + ")"
+	}
+	s.selectConfig().Expression = expr
+	return s
+}
+
+// Component keeps components of the given type (components).
+func (s *Selection) Component(typ ComponentType, names ...string) *Selection {
+	if s.cfg.Components == nil {
+		s.cfg.Components = &config.FilterComponentsConfig{}
+	}
+	components.AddCfgNames(s.cfg.Components, typ, names...)
+	return s
+}
+
+// KeepServers keeps the servers (servers).
+func (s *Selection) KeepServers() *Selection {
+	s.cfg.Servers = true
+	return s
+}
+
+// KeepSecurity keeps the global security requirements (security).
+func (s *Selection) KeepSecurity() *Selection {
+	s.cfg.Security = true
+	return s
+}
+
+// KeepTags keeps the tag definitions (tags).
+func (s *Selection) KeepTags() *Selection {
+	s.cfg.Tags = true
+	return s
+}
+
+// KeepExternalDocs keeps the external documentation (externalDocs).
+func (s *Selection) KeepExternalDocs() *Selection {
+	s.cfg.ExternalDocs = true
+	return s
+}
+
+// Config returns the filter config of the selection. The selection may be
+// changed further without affecting the returned config.
+func (s *Selection) Config() *config.FilterConfig {
+	cfg := deepcopy.Copy(s.cfg)
+	return &cfg
+}
+
+// Apply filters the spec with the config of the selection, see [Apply].
+func (s *Selection) Apply(ctx context.Context, doc *openapi3.T, opts ...Option) (*Result, error) {
+	return Apply(ctx, doc, s.Config(), opts...)
+}
+
+func (s *Selection) selectConfig() *config.SelectConfig {
+	if s.cfg.Select == nil {
+		s.cfg.Select = &config.SelectConfig{}
+	}
+	return s.cfg.Select
+}
@@ -0,0 +1,149 @@
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+const selectSpec = `
+openapi: 3.0.3
+info: {title: Store, version: "1"}
+servers: [{url: https://api.example.com}]
+security: [{apiKey: []}]
+tags: [{name: pets}, {name: billing}]
+externalDocs: {url: https://example.com/docs}
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      operationId: listPets
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+    post:
+      tags: [pets]
+      operationId: createPet
+      responses: {"201": {description: Created}}
+  /invoices:
+    get:
+      tags: [billing]
+      operationId: listInvoices
+      responses: {"200": {description: OK}}
+  /users:
+    get:
+      operationId: listUsers
+      responses: {"200": {description: OK}}
+  /store/orders:
+    get:
+      operationId: listOrders
+      responses: {"200": {description: OK}}
+components:
+  schemas:
+    Pet: {type: object}
+    Error: {type: object}
+    Unused: {type: object}
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+`
+
+// selectConfig is the config file equivalent to the selection of
+// TestSelectionMatchesConfig.
+const selectConfig = `
+paths:
+  /pets: [get]
+select:
+  tags: [billing]
+  operationIds: [listUsers]
+  expression: (method == "POST") || (path startsWith "/store")
+components:
+  schemas: [Error]
+  securitySchemes: [apiKey]
+servers: true
+security: true
+tags: true
+externalDocs: true
+`
+
+func TestSelectionMatchesConfig(t *testing.T) {
+	selection := Select().
+		Path("/pets", "get").
+		Tag("billing").
+		OperationID("listUsers").
+		Expression(`method == "POST"`).
+		Expression(`path startsWith "/store"`).
+		Component(Schema, "Error").
+		Component(SecurityScheme, "apiKey").
+		KeepServers().
+		KeepSecurity().
+		KeepTags().
+		KeepExternalDocs()
+
+	configPath := filepath.Join(t.TempDir(), ".openapi-filter.yaml")
+	if err := os.WriteFile(configPath, []byte(selectConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if got := selection.Config(); !reflect.DeepEqual(got, &loaded.FilterConfig) {
+		t.Errorf("Config() = %+v, want %+v", got, loaded.FilterConfig)
+	}
+
+	got, err := selection.Apply(context.Background(), loadTestSpec(t, selectSpec))
+	if err != nil {
+		t.Fatalf("Selection.Apply() error = %v", err)
+	}
+	want, err := Apply(context.Background(), loadTestSpec(t, selectSpec), &loaded.FilterConfig)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	gotJSON, err := json.Marshal(got.Spec)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	wantJSON, err := json.Marshal(want.Spec)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("Selection.Apply() spec = %s, want %s", gotJSON, wantJSON)
+	}
+	if !reflect.DeepEqual(got.Report, want.Report) {
+		t.Errorf("Selection.Apply() report = %+v, want %+v", got.Report, want.Report)
+	}
+
+	wantOps := []OperationReport{
+		{Method: "GET", Path: "/invoices", OperationID: "listInvoices"},
+		{Method: "GET", Path: "/pets", OperationID: "listPets"},
+		{Method: "POST", Path: "/pets", OperationID: "createPet"},
+		{Method: "GET", Path: "/store/orders", OperationID: "listOrders"},
+		{Method: "GET", Path: "/users", OperationID: "listUsers"},
+	}
+	if !reflect.DeepEqual(got.Report.Operations, wantOps) {
+		t.Errorf("operations = %+v, want %+v", got.Report.Operations, wantOps)
+	}
+}
+
+func TestSelectionConfigIsCopied(t *testing.T) {
+	selection := Select().Path("/pets", "get").Tag("pets")
+	cfg := selection.Config()
+	selection.Path("/pets", "post").Tag("billing").KeepServers()
+
+	want := &config.FilterConfig{
+		Paths:  map[string][]string{"/pets": {"get"}},
+		Select: &config.SelectConfig{Tags: []string{"pets"}},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Config() = %+v, want %+v", cfg, want)
+	}
+}
@@ -0,0 +1,135 @@
+package filter
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/walk"
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+const environmentExtension = "x-environment"
+
+// filterServers filters top-level, path and operation servers of the
+// filtered spec by URL and environment, and applies configured overrides.
+// In pass-through mode top-level servers are kept, so they are filtered
+// whenever server rules are set.
+func (f *filterer) filterServers() {
+	cfg := &f.cfg.ServerRules
+	if f.cfg.Servers || (f.cfg.PassThrough && isServersFilterSet(cfg)) {
+		if cfg.Replace != nil {
+			f.filtered.Servers = replacementServers(cfg.Replace)
+		} else {
+			f.filtered.Servers = f.selectServers("", f.doc.Servers)
+		}
+	}
+	if !isServersFilterSet(cfg) {
+		return
+	}
+
+	walk.Walk(f.filtered.Paths, func(pointer string, v reflect.Value) {
+		switch v.Type() {
+		case pathItemType:
+			pathItem := v.Addr().Interface().(*openapi3.PathItem)
+			if cfg.Replace != nil {
+				pathItem.Servers = nil
+			} else {
+				pathItem.Servers = f.selectServers(pointer, pathItem.Servers)
+			}
+		case operationType:
+			op := v.Addr().Interface().(*openapi3.Operation)
+			if op.Servers == nil {
+				return
+			}
+			if cfg.Replace != nil {
+				op.Servers = nil
+			} else if servers := f.selectServers(pointer, *op.Servers); servers != nil {
+				op.Servers = &servers
+			} else {
+				op.Servers = nil
+			}
+		}
+	})
+}
+
+func isServersFilterSet(cfg *config.ServersConfig) bool {
+	return len(cfg.URLs) > 0 || len(cfg.Environments) > 0 ||
+		cfg.Replace != nil || len(cfg.Variables) > 0
+}
+
+// selectServers returns copies of servers matching the configured URLs
+// and environments, with overridden variables.
+func (f *filterer) selectServers(pointer string, servers openapi3.Servers) openapi3.Servers {
+	cfg := &f.cfg.ServerRules
+	var selected openapi3.Servers
+	for _, server := range servers {
+		if len(cfg.URLs) > 0 && !matchesAny(cfg.URLs, server.URL) {
+			continue
+		}
+		if len(cfg.Environments) > 0 {
+			env, _ := server.Extensions[environmentExtension].(string)
+			if !slices.Contains(cfg.Environments, env) {
+				continue
+			}
+		}
+		selected = append(selected, f.overrideServerVariables(server))
+	}
+	if len(selected) < len(servers) {
+		f.logger.Debug("dropped servers",
+			zap.String("pointer", pointer),
+			zap.Int("count", len(servers)-len(selected)))
+	}
+	return selected
+}
+
+// overrideServerVariables returns a copy of the server with default values
+// of variables overridden from the configuration.
+func (f *filterer) overrideServerVariables(server *openapi3.Server) *openapi3.Server {
+	if len(f.cfg.ServerRules.Variables) == 0 || len(server.Variables) == 0 {
+		return server
+	}
+	overridden := *server
+	overridden.Variables = make(map[string]*openapi3.ServerVariable, len(server.Variables))
+	for name, variable := range server.Variables {
+		value, ok := f.cfg.ServerRules.Variables[name]
+		if !ok {
+			overridden.Variables[name] = variable
+			continue
+		}
+		if len(variable.Enum) > 0 && !slices.Contains(variable.Enum, value) {
+			f.logger.Warn("server variable override is not in enum",
+				zap.String("url", server.URL),
+				zap.String("variable", name),
+				zap.String("value", value))
+		}
+		v := *variable
+		v.Default = value
+		overridden.Variables[name] = &v
+	}
+	return &overridden
+}
+
+func replacementServers(cfgs []config.ServerConfig) openapi3.Servers {
+	servers := make(openapi3.Servers, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		server := &openapi3.Server{
+			URL:         cfg.URL,
+			Description: cfg.Description,
+		}
+		for name, variable := range cfg.Variables {
+			if server.Variables == nil {
+				server.Variables = make(map[string]*openapi3.ServerVariable)
+			}
+			server.Variables[name] = &openapi3.ServerVariable{
+				Default:     variable.Default,
+				Enum:        variable.Enum,
+				Description: variable.Description,
+			}
+		}
+		servers = append(servers, server)
+	}
+	return servers
+}
@@ -0,0 +1,45 @@
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+const serversSpec = `
+openapi: 3.0.3
+info: {title: Servers, version: "1"}
+servers:
+  - url: https://api.example.com
+  - url: https://staging.example.com
+paths:
+  /pets:
+    servers:
+      - url: https://pets.example.com
+      - url: https://pets.staging.example.com
+    get:
+      responses: {"200": {description: OK}}
+`
+
+func TestFilterServersPassThrough(t *testing.T) {
+	for _, prune := range []bool{false, true} {
+		cfg := &config.FilterConfig{
+			PassThrough:     true,
+			PruneComponents: prune,
+			ServerRules: config.ServersConfig{
+				URLs: []string{"https://api.example.com", "https://pets.example.com"},
+			},
+		}
+		result, err := Apply(context.Background(), loadTestSpec(t, serversSpec), cfg)
+		if err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		if got := result.Spec.Servers; len(got) != 1 || got[0].URL != "https://api.example.com" {
+			t.Errorf("prune=%v: servers = %v, want only https://api.example.com", prune, got)
+		}
+		if got := result.Spec.Paths.Value("/pets").Servers; len(got) != 1 || got[0].URL != "https://pets.example.com" {
+			t.Errorf("prune=%v: path servers = %v, want only https://pets.example.com", prune, got)
+		}
+	}
+}
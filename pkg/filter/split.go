@@ -0,0 +1,89 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// SplitBy defines how operations are grouped by [SplitPaths].
+type SplitBy string
+
+const (
+	SplitByTag  SplitBy = "tag"  // Group by operation tags
+	SplitByPath SplitBy = "path" // Group by the first path segment
+)
+
+const (
+	untaggedGroup = "untagged"
+	rootGroup     = "root"
+)
+
+var (
+	ErrUnknownSplitBy = errors.New("unknown split mode")
+	ErrGroupCollision = errors.New("group name collision")
+)
+
+// SplitPaths groups operations of the spec and returns the paths config
+// of every group, e.g. {"pets": {"/pets": ["GET", "POST"]}}, to filter
+// the spec by. Operations with several tags are put in the group of every
+// tag, and operations without tags in the "untagged" group. Operations of
+// the "/" path are put in the "root" group when grouped by path. Returns
+// [ErrGroupCollision] if a tag or a path segment has the name of such a
+// group.
+func SplitPaths(doc *openapi3.T, by SplitBy) (map[string]map[string][]string, error) {
+	if by != SplitByTag && by != SplitByPath {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSplitBy, by)
+	}
+	groups := make(map[string]map[string][]string)
+	// Synthetic groups are kept apart until all operations are grouped to
+	// detect tags and path segments with their names.
+	synthetic := make(map[string]map[string][]string)
+	add := func(groups map[string]map[string][]string, group, path, method string) {
+		if groups[group] == nil {
+			groups[group] = make(map[string][]string)
+		}
+		groups[group][path] = append(groups[group][path], method)
+	}
+
+	for _, path := range sortedKeys(doc.Paths.Map()) {
+		ops := doc.Paths.Value(path).Operations()
+		for _, method := range sortedKeys(ops) {
+			if by == SplitByPath {
+				if segment := firstSegment(path); segment != "" {
+					add(groups, segment, path, method)
+				} else {
+					add(synthetic, rootGroup, path, method)
+				}
+				continue
+			}
+			tags := ops[method].Tags
+			if len(tags) == 0 {
+				add(synthetic, untaggedGroup, path, method)
+			}
+			for _, tag := range tags {
+				add(groups, tag, path, method)
+			}
+		}
+	}
+
+	for group, paths := range synthetic {
+		if _, ok := groups[group]; ok {
+			what, of := "tag", "operations without tags"
+			if by == SplitByPath {
+				what, of = "first path segment", `operations of the "/" path`
+			}
+			return nil, fmt.Errorf("%w: %q is both a %s and the group of %s", ErrGroupCollision, group, what, of)
+		}
+		groups[group] = paths
+	}
+	return groups, nil
+}
+
+// firstSegment returns the first segment of the path, or "" for "/".
+func firstSegment(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return segment
+}
@@ -0,0 +1,110 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const splitSpec = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /:
+    get:
+      responses: {"200": {description: OK}}
+  /pets:
+    get:
+      tags: [pets]
+      responses: {"200": {description: OK}}
+    post:
+      tags: [pets, admin]
+      responses: {"201": {description: Created}}
+  /pets/{id}:
+    delete:
+      tags: [admin]
+      responses: {"204": {description: Deleted}}
+  /stores:
+    get:
+      responses: {"200": {description: OK}}
+`
+
+func TestSplitPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		by   SplitBy
+		want map[string]map[string][]string
+	}{
+		{
+			name: "tag",
+			by:   SplitByTag,
+			want: map[string]map[string][]string{
+				"pets":     {"/pets": {"GET", "POST"}},
+				"admin":    {"/pets": {"POST"}, "/pets/{id}": {"DELETE"}},
+				"untagged": {"/": {"GET"}, "/stores": {"GET"}},
+			},
+		},
+		{
+			name: "path",
+			by:   SplitByPath,
+			want: map[string]map[string][]string{
+				"root":   {"/": {"GET"}},
+				"pets":   {"/pets": {"GET", "POST"}, "/pets/{id}": {"DELETE"}},
+				"stores": {"/stores": {"GET"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitPaths(loadTestSpec(t, splitSpec), tt.by)
+			if err != nil {
+				t.Fatalf("SplitPaths() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitPathsErrors(t *testing.T) {
+	const collisions = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+paths:
+  /:
+    get:
+      responses: {"200": {description: OK}}
+  /root/pets:
+    get:
+      tags: [untagged]
+      responses: {"200": {description: OK}}
+`
+	tests := []struct {
+		name    string
+		by      SplitBy
+		wantErr error
+	}{
+		{name: "untagged tag", by: SplitByTag, wantErr: ErrGroupCollision},
+		{name: "root path segment", by: SplitByPath, wantErr: ErrGroupCollision},
+		{name: "unknown mode", by: "operationId", wantErr: ErrUnknownSplitBy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SplitPaths(loadTestSpec(t, collisions), tt.by); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SplitPaths() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Tags and segments named like synthetic groups are fine without
+	// operations in them.
+	doc := loadTestSpec(t, collisions)
+	doc.Paths.Delete("/")
+	if _, err := SplitPaths(doc, SplitByTag); err != nil {
+		t.Errorf("SplitPaths() error = %v", err)
+	}
+	if _, err := SplitPaths(doc, SplitByPath); err != nil {
+		t.Errorf("SplitPaths() error = %v", err)
+	}
+}
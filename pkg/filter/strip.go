@@ -0,0 +1,183 @@
+package filter
+
+import (
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"go.uber.org/zap"
+
+	"github.com/zguydev/openapi-filter/internal/components"
+	"github.com/zguydev/openapi-filter/internal/refs"
+	"github.com/zguydev/openapi-filter/internal/walk"
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+var componentsType = reflect.TypeFor[openapi3.Components]()
+
+// stripFields removes the configured kinds of fields across the filtered
+// spec, except for fields matching protected JSON pointer patterns.
+// Components referenced only by stripped fields are removed as well.
+func (f *filterer) stripFields(doc *openapi3.T) {
+	cfg := f.cfg.Strip
+	if cfg == nil {
+		return
+	}
+	fields := make(map[string]struct{})
+	for name, enabled := range map[string]bool{
+		"description":  cfg.Descriptions,
+		"summary":      cfg.Summaries,
+		"example":      cfg.Examples,
+		"examples":     cfg.Examples,
+		"externalDocs": cfg.ExternalDocs,
+	} {
+		if enabled {
+			fields[name] = struct{}{}
+		}
+	}
+
+	used := componentRefs(doc)
+	var stripped int
+	walk.Walk(doc, func(pointer string, v reflect.Value) {
+		t := v.Type()
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Name == "Extensions" && field.Type.Kind() == reflect.Map {
+				stripped += stripExtensions(cfg, pointer, v.Field(i))
+				continue
+			}
+			name := walk.JSONName(field)
+			if _, ok := fields[name]; !ok {
+				continue
+			}
+			if t == componentsType {
+				continue // Example components are removed once unreferenced
+			}
+			if isProtected(cfg.Protect, pointer+"/"+name) || v.Field(i).IsZero() {
+				continue
+			}
+			clearField(v.Field(i))
+			stripped++
+		}
+	})
+	f.logger.Debug("stripped fields", zap.Int("count", stripped))
+	f.pruneUnreferenced(doc, used)
+}
+
+// pruneUnreferenced removes components that were referenced before
+// stripping, but are not anymore, e.g. examples referenced only by
+// stripped examples fields. Protected components are kept.
+func (f *filterer) pruneUnreferenced(doc *openapi3.T, used map[string]struct{}) {
+	// Removed components may be the last ones referencing others.
+	for pruned := true; pruned && doc.Components != nil; {
+		pruned = false
+		referenced := componentRefs(doc)
+		kept := &openapi3.Components{Extensions: doc.Components.Extensions}
+		for _, compTyp := range components.ComponentTypes() {
+			for _, name := range components.ComponentNames(doc.Components, compTyp) {
+				ref := refs.ComponentRef(compTyp, name)
+				_, wasUsed := used[ref]
+				_, isUsed := referenced[ref]
+				if wasUsed && !isUsed && !isProtected(f.cfg.Strip.Protect, strings.TrimPrefix(ref, "#")) {
+					f.logger.Info("removed component unreferenced after stripping", zap.String("ref", ref))
+					pruned = true
+					continue
+				}
+				components.ProcessCopyComponent(doc.Components, kept, compTyp, name)
+			}
+		}
+		if pruned {
+			doc.Components = kept
+			if components.IsEmptyComponents(kept) {
+				doc.Components = nil
+			}
+		}
+	}
+}
+
+// componentRefs returns the refs of ref wrappers across the spec.
+func componentRefs(doc *openapi3.T) map[string]struct{} {
+	found := make(map[string]struct{})
+	walk.Walk(doc, func(_ string, v reflect.Value) {
+		if walk.IsRefWrapper(v) {
+			if ref := v.FieldByName("Ref").String(); ref != "" {
+				found[ref] = struct{}{}
+			}
+		}
+	})
+	return found
+}
+
+// clearField zeroes the field. Pointers to strings are kept pointing to an
+// empty string, as they are used for required fields (e.g. response description).
+func clearField(v reflect.Value) {
+	if v.Kind() == reflect.Pointer && v.Type().Elem().Kind() == reflect.String {
+		v.Set(reflect.New(v.Type().Elem()))
+		return
+	}
+	v.SetZero()
+}
+
+func stripExtensions(cfg *config.StripConfig, pointer string, extensions reflect.Value) (stripped int) {
+	if len(cfg.Extensions) == 0 || extensions.Len() == 0 {
+		return 0
+	}
+	for _, key := range extensions.MapKeys() {
+		name := key.String()
+		if !matchesAny(cfg.Extensions, name) ||
+			isProtected(cfg.Protect, pointer+"/"+walk.EscapePointer(name)) {
+			continue
+		}
+		extensions.SetMapIndex(key, reflect.Value{})
+		stripped++
+	}
+	return stripped
+}
+
+// matchesAny reports whether name matches any of the glob patterns.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isProtected reports whether the JSON pointer matches any of the
+// protected patterns. In patterns, "*" matches a single reference token
+// and "**" matches any number of tokens.
+func isProtected(patterns []string, pointer string) bool {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for _, pattern := range patterns {
+		if matchPointer(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), tokens) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPointer(pattern, tokens []string) bool {
+	if len(pattern) == 0 {
+		return len(tokens) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(tokens); i++ {
+			if matchPointer(pattern[1:], tokens[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(tokens) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], tokens[0]); !ok {
+		return false
+	}
+	return matchPointer(pattern[1:], tokens[1:])
+}
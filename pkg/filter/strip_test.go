@@ -0,0 +1,155 @@
+package filter
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/zguydev/openapi-filter/internal/components"
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+const stripSpec = `
+openapi: 3.0.3
+info: {title: Pets, version: "1", description: About pets}
+paths:
+  /pets:
+    get:
+      summary: List pets
+      description: Lists all pets
+      x-internal-owner: pets-team
+      x-codegen: {name: list}
+      parameters:
+        - name: q
+          in: query
+          description: Query
+          schema: {type: string}
+          examples:
+            one: {$ref: "#/components/examples/Query"}
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pet"}
+              examples:
+                cat: {$ref: "#/components/examples/Cat"}
+components:
+  schemas:
+    Pet:
+      type: object
+      description: A pet
+      example: {name: cat}
+      properties:
+        name: {type: string, description: Name}
+  examples:
+    Cat: {value: {name: cat}}
+    Query: {value: cat}
+    Unused: {value: unused}
+`
+
+func TestStripFields(t *testing.T) {
+	tests := []struct {
+		name         string
+		strip        config.StripConfig
+		wantExamples []string // Names of example components kept
+	}{
+		{
+			name:         "descriptions",
+			strip:        config.StripConfig{Descriptions: true, Protect: []string{"/info/description", "/components/schemas/*/properties/**"}},
+			wantExamples: []string{"Cat", "Query", "Unused"},
+		},
+		{
+			name:         "examples",
+			strip:        config.StripConfig{Examples: true},
+			wantExamples: []string{"Unused"},
+		},
+		{
+			name:         "protected examples",
+			strip:        config.StripConfig{Examples: true, Protect: []string{"/paths/*/get/parameters/**", "/components/examples/Cat"}},
+			wantExamples: []string{"Cat", "Query", "Unused"},
+		},
+		{
+			name:         "extensions",
+			strip:        config.StripConfig{Extensions: []string{"x-internal-*"}},
+			wantExamples: []string{"Cat", "Query", "Unused"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.FilterConfig{
+				Paths:      map[string][]string{"/pets": {"get"}},
+				Components: &config.FilterComponentsConfig{Examples: []string{"Unused"}},
+				Strip:      &tt.strip,
+			}
+			result, err := Apply(context.Background(), loadTestSpec(t, stripSpec), cfg)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			doc := result.Spec
+			op := doc.Paths.Value("/pets").Get
+			pet := doc.Components.Schemas["Pet"].Value
+			response := op.Responses.Status(200).Value
+			media := response.Content.Get("application/json")
+
+			s := tt.strip
+			assertField(t, "info description", doc.Info.Description, "About pets", isProtected(s.Protect, "/info/description") || !s.Descriptions)
+			assertField(t, "operation description", op.Description, "Lists all pets", !s.Descriptions)
+			assertField(t, "schema description", pet.Description, "A pet", !s.Descriptions)
+			assertField(t, "property description", pet.Properties["name"].Value.Description, "Name",
+				isProtected(s.Protect, "/components/schemas/Pet/properties/name/description") || !s.Descriptions)
+			if response.Description == nil {
+				t.Error("response description is nil, want empty")
+			}
+			if got := pet.Example != nil; got != !s.Examples {
+				t.Errorf("schema example kept = %v, want %v", got, !s.Examples)
+			}
+			if got := media.Examples != nil; got != !s.Examples {
+				t.Errorf("media type examples kept = %v, want %v", got, !s.Examples)
+			}
+			if _, ok := op.Extensions["x-internal-owner"]; ok == (len(s.Extensions) > 0) {
+				t.Errorf("x-internal-owner kept = %v, want %v", ok, len(s.Extensions) == 0)
+			}
+			if _, ok := op.Extensions["x-codegen"]; !ok {
+				t.Error("x-codegen removed, want kept")
+			}
+
+			got := components.ComponentNames(doc.Components, components.ContentTypeExample)
+			if !slices.Equal(got, tt.wantExamples) {
+				t.Errorf("example components = %v, want %v", got, tt.wantExamples)
+			}
+		})
+	}
+}
+
+func assertField(t *testing.T, name, got, value string, kept bool) {
+	t.Helper()
+	want := ""
+	if kept {
+		want = value
+	}
+	if got != want {
+		t.Errorf("%s = %q, want %q", name, got, want)
+	}
+}
+
+func TestIsProtected(t *testing.T) {
+	tests := []struct {
+		pointer string
+		want    bool
+	}{
+		{pointer: "/info/description", want: true},
+		{pointer: "/info/summary"},
+		{pointer: "/paths/~1pets/get/description", want: true},
+		{pointer: "/paths/~1pets/get/responses/200/description"},
+		{pointer: "/components/schemas/Pet", want: true},
+		{pointer: "/components/schemas/Pet/properties/name/description", want: true},
+		{pointer: "/components/schemas/Pets/description"},
+	}
+	patterns := []string{"/info/description", "/paths/*/get/description", "/components/schemas/Pet/**"}
+	for _, tt := range tests {
+		if got := isProtected(patterns, tt.pointer); got != tt.want {
+			t.Errorf("isProtected(%q) = %v, want %v", tt.pointer, got, tt.want)
+		}
+	}
+}
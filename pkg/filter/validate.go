@@ -0,0 +1,107 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/zguydev/openapi-filter/internal/components"
+	"github.com/zguydev/openapi-filter/internal/refs"
+)
+
+// Validate validates a filtered spec and returns all found violations.
+// In addition to [openapi3.T.Validate], it checks that every local ref
+// points to an existing component and that every security requirement
+// uses a defined security scheme.
+func Validate(ctx context.Context, doc *openapi3.T) (violations []error) {
+	if err := doc.Validate(ctx); err != nil {
+		violations = append(violations, err)
+	}
+	violations = append(violations, danglingRefs(doc)...)
+	violations = append(violations, undefinedSecuritySchemes(doc)...)
+	return violations
+}
+
+// danglingRefs finds local refs to components missing in the spec.
+func danglingRefs(doc *openapi3.T) (violations []error) {
+	collector := refs.NewRefsCollector()
+	if doc.Paths != nil {
+		for _, path := range sortedKeys(doc.Paths.Map()) {
+			collector.SetOrigin(path)
+			collector.CollectPathItem(doc.Paths.Value(path))
+		}
+	}
+	if doc.Components != nil {
+		// Every component is an origin, so dangling refs are reported with
+		// the component they are referenced from.
+		for _, compTyp := range components.ComponentTypes() {
+			for _, name := range components.ComponentNames(doc.Components, compTyp) {
+				collector.SetOrigin(refs.ComponentRef(compTyp, name))
+				collector.CollectComponent(doc.Components, compTyp, name)
+			}
+		}
+	}
+
+	for _, ref := range sortedKeys(collector.Refs()) {
+		if !strings.HasPrefix(ref, "#/") {
+			continue // external refs are not checked
+		}
+		def, name, ok := refs.ParseRef(ref)
+		if !ok {
+			violations = append(violations, fmt.Errorf("malformed ref %q", ref))
+			continue
+		}
+		compTyp, ok := components.ComponentDefToType(def)
+		if !ok {
+			violations = append(violations, fmt.Errorf("ref %q to unknown component type", ref))
+			continue
+		}
+		if !hasComponent(doc.Components, compTyp, name) {
+			chain := collector.Chain(ref)
+			violations = append(violations, fmt.Errorf("dangling ref %q referenced from %s",
+				ref, strings.Join(chain[:len(chain)-1], " > ")))
+		}
+	}
+	return violations
+}
+
+func hasComponent(comps *openapi3.Components, typ components.ComponentType, name string) bool {
+	if comps == nil {
+		return false
+	}
+	names := components.ComponentNames(comps, typ)
+	i := sort.SearchStrings(names, name)
+	return i < len(names) && names[i] == name
+}
+
+// undefinedSecuritySchemes finds security requirements using security
+// schemes missing in the spec.
+func undefinedSecuritySchemes(doc *openapi3.T) (violations []error) {
+	check := func(location string, reqs openapi3.SecurityRequirements) {
+		for _, req := range reqs {
+			for _, name := range sortedKeys(req) {
+				if doc.Components == nil || doc.Components.SecuritySchemes[name] == nil {
+					violations = append(violations, fmt.Errorf(
+						"undefined security scheme %q used by %s", name, location))
+				}
+			}
+		}
+	}
+
+	check("global security", doc.Security)
+	if doc.Paths == nil {
+		return violations
+	}
+	for _, path := range sortedKeys(doc.Paths.Map()) {
+		ops := doc.Paths.Value(path).Operations()
+		for _, method := range sortedKeys(ops) {
+			if op := ops[method]; op.Security != nil {
+				check(OperationSelector(method, path), *op.Security)
+			}
+		}
+	}
+	return violations
+}
@@ -0,0 +1,89 @@
+package filter
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"github.com/zguydev/openapi-filter/pkg/config"
+)
+
+const validateSpec = `
+openapi: 3.0.3
+info: {title: Pets, version: "1"}
+security: [{apiKey: []}]
+paths:
+  /pets:
+    get:
+      security: [{oauth: []}]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: "#/components/schemas/Pets"}
+components:
+  schemas:
+    Pets: {type: array, items: {$ref: "#/components/schemas/Pet"}}
+    Pet: {type: object}
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-API-Key}
+    oauth:
+      type: oauth2
+      flows: {clientCredentials: {tokenUrl: https://example.com/token, scopes: {}}}
+`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		drop func(comps *openapi3.Components)
+		want []string
+	}{
+		{
+			name: "valid",
+			drop: func(*openapi3.Components) {},
+		},
+		{
+			name: "dangling ref",
+			drop: func(comps *openapi3.Components) { delete(comps.Schemas, "Pet") },
+			want: []string{`dangling ref "#/components/schemas/Pet" referenced from #/components/schemas/Pets`},
+		},
+		{
+			name: "undefined security schemes",
+			drop: func(comps *openapi3.Components) { comps.SecuritySchemes = nil },
+			want: []string{
+				`undefined security scheme "apiKey" used by global security`,
+				`undefined security scheme "oauth" used by GET /pets`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.FilterConfig{
+				Paths:      map[string][]string{"/pets": {"get"}},
+				Security:   true,
+				Components: &config.FilterComponentsConfig{SecuritySchemes: []string{"apiKey", "oauth"}},
+			}
+			// The custom stage filters out components the spec refers to.
+			drop := TransformerFunc(func(_ context.Context, doc *openapi3.T) error {
+				tt.drop(doc.Components)
+				return nil
+			})
+			result, err := Apply(context.Background(), loadTestSpec(t, validateSpec), cfg,
+				WithStageAfter(StageSelect, "drop", drop))
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			var got []string
+			for _, violation := range Validate(context.Background(), result.Spec) {
+				got = append(got, violation.Error())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}